@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var cachePruneOlderThan string
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the on-disk artifact cache",
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cached artifacts older than a time period",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		olderThan, err := healthcheck.ParseTimePeriod(cachePruneOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than value: %w", err)
+		}
+
+		dir, err := cacheDir()
+		if err != nil {
+			return err
+		}
+
+		removed, err := healthcheck.NewCache(dir).Prune(olderThan)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Removed %d cached artifacts from %s\n", removed, dir)
+		return nil
+	},
+}
+
+func init() {
+	cachePruneCmd.Flags().StringVar(&cachePruneOlderThan, "older-than", "30d", "Remove artifacts cached longer ago than this period (e.g., 24h, 30d, 4w)")
+
+	cacheCmd.AddCommand(cachePruneCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// configPath is the configuration file given with --config
+var configPath string
+
+// fileConfig is the loaded configuration file, empty when there is none
+var fileConfig = &healthcheck.Config{}
+
+// loadedConfigPath is the configuration file fileConfig was read from, "" when none exists
+var loadedConfigPath string
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration file",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective aliases, source endpoints and default flags",
+	Long: `Print the effective configuration as YAML: the job regex aliases (built-in and from
+the configuration file), the source endpoints after applying the configuration file and
+the global flags, and the default flags set by the configuration file.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		effective := healthcheck.Config{
+			Aliases: healthcheck.JobRegexAliases,
+			Sources: healthcheck.SourcesConfig{
+				ProwURL:     prowConfig.ProwURL,
+				GCSWebURL:   prowConfig.GCSWebURL,
+				StorageURL:  prowConfig.StorageURL,
+				Bucket:      prowConfig.Bucket,
+				Org:         prowConfig.Org,
+				Repo:        prowConfig.Repo,
+				CIHealthURL: prowConfig.CIHealthURL,
+			},
+			Defaults: fileConfig.Defaults,
+			Commands: fileConfig.Commands,
+		}
+		data, err := yaml.Marshal(effective)
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+
+		if loadedConfigPath != "" {
+			fmt.Printf("# Config file: %s\n", loadedConfigPath)
+		} else {
+			fmt.Printf("# No config file found, showing built-in defaults\n")
+		}
+		fmt.Print(string(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path of the configuration file (default: $XDG_CONFIG_HOME/healthcheck/config.yaml)")
+
+	configCmd.AddCommand(configShowCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// loadConfig reads the configuration file, adds its aliases and sets the flags of cmd
+// that were not given on the command line to the configured source endpoints and defaults
+func loadConfig(cmd *cobra.Command) error {
+	path := configPath
+	if path == "" {
+		var err error
+		if path, err = healthcheck.DefaultConfigPath(); err != nil {
+			// Without a config directory there is no default config file to load
+			return nil
+		}
+	}
+
+	loaded, err := healthcheck.LoadConfig(path, configPath != "")
+	if err != nil {
+		return err
+	}
+	if loaded == nil {
+		return nil
+	}
+	fileConfig, loadedConfigPath = loaded, path
+	fileConfig.ApplyAliases()
+
+	if err := setFlagDefaults(cmd.Flags(), cmd.Root().PersistentFlags(), loaded.Sources.Flags(), "sources"); err != nil {
+		return err
+	}
+	if err := setFlagDefaults(cmd.Flags(), cmd.Root().PersistentFlags(), loaded.Defaults, "defaults"); err != nil {
+		return err
+	}
+	// Subcommands are keyed by their path below the root, such as "cache prune"
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	return setFlagDefaults(cmd.Flags(), cmd.Flags(), loaded.Commands[name], "commands."+name)
+}
+
+// setFlagDefaults sets the flags in values that were not given on the command line.
+// Every flag in values must be known to known.
+func setFlagDefaults(flags, known *pflag.FlagSet, values map[string]string, section string) error {
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if known.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag %q in %s of the config file", name, section)
+		}
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := flags.Set(name, values[name]); err != nil {
+			return fmt.Errorf("invalid value for %s in %s of the config file: %w", name, section, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	durationsSincePeriod  string
+	durationsLimit        int
+	durationsRecentPeriod string
+	durationsThreshold    float64
+	durationsMinIncrease  time.Duration
+	durationsTop          int
+	durationsOutputFormat string
+	durationsFromDB       bool
+)
+
+var durationsCmd = &cobra.Command{
+	Use:   "durations [job-name]",
+	Short: "Report test duration percentiles and slow-test regressions for a lane",
+	Long: `Aggregate the junit durations of every test across the runs of a lane into
+p50/p90/max percentiles, flag tests whose median duration in the --recent window grew
+by more than --threshold percent against the earlier runs, and report the suite wall
+time of each run.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		jobName := args[0]
+
+		timePeriod, err := healthcheck.ParseTimePeriod(durationsSincePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid time period: %w", err)
+		}
+		recentWindow, err := healthcheck.ParseTimePeriod(durationsRecentPeriod)
+		if err != nil {
+			return fmt.Errorf("invalid recent window: %w", err)
+		}
+
+		source := newSource()
+		if durationsFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			source = db
+		}
+
+		var runs []healthcheck.JobRun
+		if timePeriod > 0 {
+			runs, err = healthcheck.FetchJobHistoryWithTimePeriod(ctx, source, jobName, timePeriod, 1000)
+		} else {
+			runs, err = healthcheck.FetchJobHistory(ctx, source, jobName, durationsLimit)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch job history for %s: %w", jobName, err)
+		}
+
+		summary, err := healthcheck.AnalyzeLaneRuns(ctx, source, runs, parallel)
+		if err != nil {
+			return fmt.Errorf("failed to analyze lane runs: %w", err)
+		}
+
+		config := healthcheck.DefaultDurationConfig
+		config.RecentWindow = recentWindow
+		config.RegressionThreshold = durationsThreshold
+		config.MinIncrease = durationsMinIncrease
+		report := healthcheck.AnalyzeDurations(jobName, summary.Runs, config)
+
+		if durationsOutputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON output: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+
+		healthcheck.FormatDurationReport(report, durationsTop)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(durationsCmd)
+
+	durationsCmd.Flags().StringVarP(&durationsSincePeriod, "since", "s", "1w", "Analyze all runs within time period (e.g., 24h, 2d, 1w); empty to use --limit")
+	durationsCmd.Flags().IntVarP(&durationsLimit, "limit", "l", 50, "Number of recent runs to analyze when --since is empty")
+	durationsCmd.Flags().StringVar(&durationsRecentPeriod, "recent", "24h", "Window before the newest run compared against the earlier runs")
+	durationsCmd.Flags().Float64Var(&durationsThreshold, "threshold", 50, "Flag tests whose median duration grew by at least this percentage")
+	durationsCmd.Flags().DurationVar(&durationsMinIncrease, "min-increase", healthcheck.DefaultDurationConfig.MinIncrease, "Ignore regressions whose median duration grew by less than this (e.g., 10s, 1m)")
+	durationsCmd.Flags().IntVar(&durationsTop, "top", 20, "Number of tests listed per section")
+	durationsCmd.Flags().StringVarP(&durationsOutputFormat, "output", "o", "text", "Output format: text or json")
+	durationsCmd.Flags().BoolVar(&durationsFromDB, "from-db", false, "Answer from the local history database instead of Prow")
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"healthcheck/pkg/healthcheck"
 
@@ -19,63 +23,204 @@ var (
 	laneSummary          bool
 	laneOutputFormat     string
 	laneJobType          string
+	laneLabels           []string
+	laneGroupBy          string
+	laneFlakes           bool
+	laneFromDB           bool
 )
 
 var laneCmd = &cobra.Command{
-	Use:   "lane [job-name]",
-	Short: "Analyze recent job runs for a specific lane",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(_ *cobra.Command, args []string) error {
-		jobName := args[0]
-
-		// Parse time period if provided
-		timePeriod, err := healthcheck.ParseTimePeriod(laneSincePeriod)
+	Use:   "lane [job-name-or-alias...]",
+	Short: "Analyze recent job runs for a lane, or compare several lanes",
+	Long: `Analyze the recent runs of a lane. Given several job names, or an alias or regex
+matching several lanes, the lanes are analyzed concurrently and compared side by side:
+their failure and infrastructure failure rates, their top failures and the tests failing
+in only one of them. Lanes of aliases and regexes are taken from ci-health, or from the
+history database with --from-db.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		source := newSource()
+		if laneFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			source = db
+		}
+
+		lanes, err := resolveLanes(ctx, source, args)
 		if err != nil {
-			return fmt.Errorf("invalid time period: %w", err)
+			return err
+		}
+		if len(lanes) == 1 {
+			return analyzeLane(ctx, source, lanes[0], lanes[0], laneLimit)
+		}
+		return compareLanes(ctx, source, lanes, laneLimit)
+	},
+}
+
+// resolveLanes returns the lanes named by args: job names are taken as they are, aliases
+// and regexes are replaced by the known lanes they match
+func resolveLanes(ctx context.Context, source healthcheck.Source, args []string) ([]string, error) {
+	var lanes []string
+	add := func(lane string) {
+		if !slices.Contains(lanes, lane) {
+			lanes = append(lanes, lane)
 		}
+	}
 
-		// Fetch job history with smart pagination based on time period
-		var runs []healthcheck.JobRun
-		if timePeriod > 0 {
-			// Use time-based pagination with reasonable max limit to prevent runaway
-			maxLimit := 1000 // Safety limit to prevent excessive API calls
-			runs, err = healthcheck.FetchJobHistoryWithTimePeriod(jobName, timePeriod, maxLimit)
-		} else {
-			// Use regular limit-based fetching
-			runs, err = healthcheck.FetchJobHistory(jobName, laneLimit)
+	for _, arg := range args {
+		if !healthcheck.IsJobAlias(arg) && !isJobRegex(arg) {
+			add(arg)
+			continue
 		}
+		jobRegex, err := regexp.Compile(healthcheck.ResolveJobAlias(arg))
 		if err != nil {
-			return fmt.Errorf("failed to fetch job history for %s: %w", jobName, err)
+			return nil, fmt.Errorf("invalid job name regex provided: %w", err)
 		}
-
-		// Analyze each run (this populates JobType field)
-		summary, err := healthcheck.AnalyzeLaneRuns(runs)
+		matching, err := healthcheck.KnownLanes(ctx, source, prowConfig.HealthURL(), jobRegex)
 		if err != nil {
-			return fmt.Errorf("failed to analyze lane runs: %w", err)
+			return nil, err
 		}
-
-		// Filter by job type if specified (after analysis to ensure JobType is populated)
-		if laneJobType != "" {
-			summary = healthcheck.FilterLaneSummaryByJobType(summary, laneJobType)
+		if len(matching) == 0 {
+			return nil, fmt.Errorf("no lanes match %s", arg)
+		}
+		for _, lane := range matching {
+			add(lane)
 		}
+	}
+	return lanes, nil
+}
+
+// isJobRegex reports whether a lane argument is a regex rather than a job name. Dots are
+// common in job names, so only the other regex metacharacters are considered.
+func isJobRegex(arg string) bool {
+	return strings.ContainsAny(arg, `*+?|^$[](){}\`)
+}
+
+// analyzeLane fetches and analyzes the runs of a lane from source and displays them
+// according to the lane flags, titled with title
+func analyzeLane(ctx context.Context, source healthcheck.Source, jobName, title string, limit int) error {
+	config, err := laneDisplayConfig()
+	if err != nil {
+		return err
+	}
+	summary, err := fetchLaneSummary(ctx, source, jobName, title, limit, parallel)
+	if err != nil {
+		return err
+	}
+
+	// Display results
+	if laneOutputFormat == "json" {
+		return printJSON(laneJSONOutput(title, summary, config))
+	} else {
+		healthcheck.FormatLaneOutput(title, summary, config)
+		return nil
+	}
+}
+
+// compareLanes analyzes several lanes concurrently and displays their comparison. When a
+// display flag is given, the output of every lane is shown before the comparison.
+func compareLanes(ctx context.Context, source healthcheck.Source, lanes []string, limit int) error {
+	config, err := laneDisplayConfig()
+	if err != nil {
+		return err
+	}
+	if _, err := healthcheck.ParseTimePeriod(laneSincePeriod); err != nil {
+		return fmt.Errorf("invalid time period: %w", err)
+	}
+
+	summaries, errs := healthcheck.FetchLaneSummaries(ctx, lanes, parallel, func(ctx context.Context, lane string, laneParallel int) (*healthcheck.LaneSummary, error) {
+		return fetchLaneSummary(ctx, source, lane, lane, limit, laneParallel)
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	comparison := healthcheck.CompareLanes(lanes, summaries, errs)
 
-		// Configure lane display options
-		config := healthcheck.LaneDisplayConfig{
-			CountFailures:        laneCountFailures,
-			DisplayOnlyURLs:      laneDisplayOnlyURLs,
-			DisplayOnlyTestNames: laneDisplayOnlyTestNames,
-			DisplayFailures:      laneDisplayFailures,
-			Summary:              laneSummary,
+	detailed := config.CountFailures || config.DisplayOnlyURLs || config.DisplayOnlyTestNames ||
+		config.DisplayFailures || config.Summary || config.GroupBySignature || config.Flakes
+
+	if laneOutputFormat == "json" {
+		output := map[string]interface{}{"comparison": comparison}
+		if detailed {
+			var outputs []interface{}
+			for i, lane := range lanes {
+				if errs[i] == nil {
+					outputs = append(outputs, laneJSONOutput(lane, summaries[i], config))
+				}
+			}
+			output["lanes"] = outputs
 		}
+		return printJSON(output)
+	}
 
-		// Display results
-		if laneOutputFormat == "json" {
-			return outputLaneJSON(jobName, summary, config)
-		} else {
-			healthcheck.FormatLaneOutput(jobName, summary, config)
-			return nil
+	if detailed {
+		for i, lane := range lanes {
+			if errs[i] != nil {
+				continue
+			}
+			healthcheck.FormatLaneOutput(lane, summaries[i], config)
+			fmt.Println()
 		}
-	},
+	}
+	healthcheck.FormatLaneComparison(comparison)
+	return nil
+}
+
+// fetchLaneSummary fetches and analyzes the runs of a lane from source according to the
+// lane flags, fetching the artifacts of runParallel runs at a time and using title in errors
+func fetchLaneSummary(ctx context.Context, source healthcheck.Source, jobName, title string, limit, runParallel int) (*healthcheck.LaneSummary, error) {
+	// Parse time period if provided
+	timePeriod, err := healthcheck.ParseTimePeriod(laneSincePeriod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time period: %w", err)
+	}
+
+	// Fetch job history with smart pagination based on time period
+	var runs []healthcheck.JobRun
+	if timePeriod > 0 {
+		// Use time-based pagination with reasonable max limit to prevent runaway
+		maxLimit := 1000 // Safety limit to prevent excessive API calls
+		runs, err = healthcheck.FetchJobHistoryWithTimePeriod(ctx, source, jobName, timePeriod, maxLimit)
+	} else {
+		// Use regular limit-based fetching
+		runs, err = healthcheck.FetchJobHistory(ctx, source, jobName, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch job history for %s: %w", title, err)
+	}
+
+	// Analyze each run (this populates JobType field)
+	summary, err := healthcheck.AnalyzeLaneRuns(ctx, source, runs, runParallel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze lane runs: %w", err)
+	}
+
+	// Filter by job type if specified (after analysis to ensure JobType is populated)
+	if laneJobType != "" {
+		summary = healthcheck.FilterLaneSummaryByJobType(summary, laneJobType)
+	}
+	return healthcheck.FilterLaneSummaryByLabels(summary, laneLabels), nil
+}
+
+// laneDisplayConfig returns the lane display options selected by the lane flags
+func laneDisplayConfig() (healthcheck.LaneDisplayConfig, error) {
+	bySignature, err := groupBySignature(laneGroupBy)
+	if err != nil {
+		return healthcheck.LaneDisplayConfig{}, err
+	}
+	return healthcheck.LaneDisplayConfig{
+		CountFailures:        laneCountFailures,
+		DisplayOnlyURLs:      laneDisplayOnlyURLs,
+		DisplayOnlyTestNames: laneDisplayOnlyTestNames,
+		DisplayFailures:      laneDisplayFailures,
+		Summary:              laneSummary,
+		GroupBySignature:     bySignature,
+		Flakes:               laneFlakes,
+	}, nil
 }
 
 func init() {
@@ -88,12 +233,16 @@ func init() {
 	laneCmd.Flags().BoolVar(&laneSummary, "summary", false, "Display a concise summary of test runs and failure patterns")
 	laneCmd.Flags().StringVarP(&laneOutputFormat, "output", "o", "text", "Output format: text or json")
 	laneCmd.Flags().StringVarP(&laneJobType, "type", "t", "", "Filter jobs by type (e.g., batch, presubmit, periodic, postsubmit)")
+	laneCmd.Flags().StringSliceVar(&laneLabels, "label", nil, "Only include test failures carrying one of these Ginkgo labels (e.g., sig-compute)")
+	laneCmd.Flags().StringVar(&laneGroupBy, "group-by", "test", "Group failures by test name or by normalized failure message: test or signature")
+	laneCmd.Flags().BoolVar(&laneFlakes, "flakes", false, "List tests that passed and failed on the same commits, with the runs as evidence")
+	laneCmd.Flags().BoolVar(&laneFromDB, "from-db", false, "Answer from the local history database instead of Prow")
 
 	rootCmd.AddCommand(laneCmd)
 }
 
-// outputLaneJSON outputs lane data in JSON format
-func outputLaneJSON(jobName string, summary *healthcheck.LaneSummary, config healthcheck.LaneDisplayConfig) error {
+// laneJSONOutput returns the JSON output of lane data selected by config
+func laneJSONOutput(jobName string, summary *healthcheck.LaneSummary, config healthcheck.LaneDisplayConfig) interface{} {
 	var output interface{}
 
 	if config.DisplayOnlyURLs {
@@ -118,6 +267,18 @@ func outputLaneJSON(jobName string, summary *healthcheck.LaneSummary, config hea
 			"job_name":   jobName,
 			"test_names": testNames,
 		}
+	} else if config.Flakes {
+		// Tests that passed and failed on the same commit set, with evidence runs
+		output = map[string]interface{}{
+			"job_name":    jobName,
+			"flaky_tests": summary.Flakes,
+		}
+	} else if config.GroupBySignature {
+		// Failures clustered by signature, with the affected tests of each cluster
+		output = map[string]interface{}{
+			"job_name":           jobName,
+			"signature_clusters": healthcheck.ClusterFailures(summary.AllFailures),
+		}
 	} else if config.CountFailures {
 		// Count failures by test name
 		output = map[string]interface{}{
@@ -133,6 +294,8 @@ func outputLaneJSON(jobName string, summary *healthcheck.LaneSummary, config hea
 			"failed_runs":     summary.FailedRuns,
 			"failure_rate":    summary.FailureRate,
 			"test_failures":   summary.TestFailures,
+			"test_stats":      summary.TestStats,
+			"flaky_tests":     summary.Flakes,
 			"all_failures":    summary.AllFailures,
 			"top_failures":    summary.TopFailures,
 			"first_run_time":  summary.FirstRunTime,
@@ -147,6 +310,11 @@ func outputLaneJSON(jobName string, summary *healthcheck.LaneSummary, config hea
 		}
 	}
 
+	return output
+}
+
+// printJSON prints output as indented JSON
+func printJSON(output interface{}) error {
 	jsonBytes, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON output: %w", err)
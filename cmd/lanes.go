@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lanesSincePeriod  string
+	lanesLimit        int
+	lanesOutputFormat string
+	lanesFromDB       bool
+)
+
+var lanesCmd = &cobra.Command{
+	Use:   "lanes [regex-or-alias]",
+	Short: "List known lanes with their type, last run and recent failure rate",
+	Long: `Discover lanes from the ci-health results and from the pr-logs/directory and logs
+listings of the artifact bucket (or the history database with --from-db), and show the
+type, newest run and failure rate within --since of every lane matching the optional
+regex or alias.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern := ".*"
+		if len(args) > 0 {
+			pattern = args[0]
+		}
+		// Resolve job regex aliases
+		pattern = healthcheck.ResolveJobAlias(pattern)
+		jobRegex, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid job name regex provided: %w", err)
+		}
+
+		timePeriod, err := healthcheck.ParseTimePeriod(lanesSincePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid time period: %w", err)
+		}
+
+		config := healthcheck.LaneDiscoveryConfig{
+			HealthURL:  prowConfig.HealthURL(),
+			JobRegex:   jobRegex,
+			TimePeriod: timePeriod,
+			RunLimit:   lanesLimit,
+			Parallel:   parallel,
+		}
+
+		source := newSource()
+		if lanesFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			source = db
+			config.HealthURL = ""
+		}
+
+		lanes, err := healthcheck.DiscoverLanes(cmd.Context(), source, config)
+		if err != nil {
+			return err
+		}
+
+		if lanesOutputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(lanes, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON output: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+
+		healthcheck.FormatLanes(lanes, lanesSincePeriod)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lanesCmd)
+
+	lanesCmd.Flags().StringVarP(&lanesSincePeriod, "since", "s", "1w", "Window the failure rate is computed over (e.g., 24h, 2d, 1w)")
+	lanesCmd.Flags().IntVarP(&lanesLimit, "limit", "l", 20, "Maximum number of recent runs inspected per lane")
+	lanesCmd.Flags().StringVarP(&lanesOutputFormat, "output", "o", "text", "Output format: text or json")
+	lanesCmd.Flags().BoolVar(&lanesFromDB, "from-db", false, "List the lanes of the local history database instead of Prow")
+}
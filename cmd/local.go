@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	localLimit   int
+	localJobName string
+)
+
+var localCmd = &cobra.Command{
+	Use:   "local [directory]",
+	Short: "Analyze job runs from a local directory of Prow artifacts",
+	Long: `Analyze build folders downloaded from Prow (for example with gsutil) or copied
+from clusters Prow cannot reach. Every directory below [directory] that holds a
+prowjob.json, finished.json or build-log.txt is treated as one run; junit*.xml files
+in it provide the test results. The output matches the lane command.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		title := dir
+		if localJobName != "" {
+			title = localJobName
+		}
+
+		return analyzeLane(cmd.Context(), healthcheck.NewLocalSource(dir), localJobName, title, localLimit)
+	},
+}
+
+func init() {
+	localCmd.Flags().IntVarP(&localLimit, "limit", "l", 0, "Number of most recent builds to analyze (0 for all, ignored when --since is used)")
+	localCmd.Flags().StringVarP(&localJobName, "job", "j", "", "Only analyze builds of this job")
+	// The display flags are shared with the lane command
+	localCmd.Flags().BoolVarP(&laneCountFailures, "count", "c", false, "Count specific test failures")
+	localCmd.Flags().BoolVarP(&laneDisplayOnlyURLs, "url", "u", false, "Display only failed build URLs")
+	localCmd.Flags().BoolVarP(&laneDisplayOnlyTestNames, "name", "n", false, "Display only failed test names")
+	localCmd.Flags().BoolVarP(&laneDisplayFailures, "failures", "f", false, "Print any captured failure context")
+	localCmd.Flags().StringVarP(&laneSincePeriod, "since", "s", "", "Only analyze builds started within time period (e.g., 24h, 2d, 1w)")
+	localCmd.Flags().BoolVar(&laneSummary, "summary", false, "Display a concise summary of test runs and failure patterns")
+	localCmd.Flags().StringVarP(&laneOutputFormat, "output", "o", "text", "Output format: text or json")
+	localCmd.Flags().StringVarP(&laneJobType, "type", "t", "", "Filter jobs by type (e.g., batch, presubmit, periodic, postsubmit)")
+	localCmd.Flags().StringVar(&laneGroupBy, "group-by", "test", "Group failures by test name or by normalized failure message: test or signature")
+	localCmd.Flags().StringSliceVar(&laneLabels, "label", nil, "Only include test failures carrying one of these Ginkgo labels (e.g., sig-compute)")
+	localCmd.Flags().BoolVar(&laneFlakes, "flakes", false, "List tests that passed and failed on the same commits, with the builds as evidence")
+
+	rootCmd.AddCommand(localCmd)
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"time"
 
 	"healthcheck/pkg/mcp"
 
@@ -10,10 +13,11 @@ import (
 )
 
 var (
-	mcpPort   int
-	mcpHost   string
-	mcpStdio  bool
-	mcpDebug  bool
+	mcpPort            int
+	mcpHost            string
+	mcpStdio           bool
+	mcpToken           string
+	mcpShutdownTimeout time.Duration
 )
 
 var mcpCmd = &cobra.Command{
@@ -34,12 +38,17 @@ This enables LLM-powered workflows like:
 - "Analyze recent failures in pull-kubevirt-e2e-k8s-1.32-sig-compute"
 - "Compare this week's failure rate to last week for unit tests"
 - "Find all migration-related failures across all jobs"
-- "Generate a release health report for all SIG areas"`,
-	RunE: func(_ *cobra.Command, _ []string) error {
+- "Generate a release health report for all SIG areas"
+
+By default the server talks to a single client over stdio. With --port it serves HTTP
+instead, so that several editors and agents can share one server: streamable HTTP at
+/mcp and SSE at /sse. Set --token (or HEALTHCHECK_MCP_TOKEN) to require clients to
+send it as a bearer token.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
 		// Create and configure MCP server
-		server := mcp.NewHealthcheckMCPServer()
+		server := mcp.NewHealthcheckMCPServer(prowConfig, newSource(), parallel, quarantineSource)
 		
-		if mcpDebug {
+		if debug {
 			fmt.Fprintf(os.Stderr, "Starting healthcheck MCP server...\n")
 			fmt.Fprintf(os.Stderr, "Available tools:\n")
 			fmt.Fprintf(os.Stderr, "- analyze_job_lane: Analyze job failures with patterns\n")
@@ -50,26 +59,49 @@ This enables LLM-powered workflows like:
 			fmt.Fprintf(os.Stderr, "- get_failure_source_context: Parse junit failures and generate GitHub URLs\n")
 			fmt.Fprintf(os.Stderr, "- analyze_failure_trends: Analyze failure trends and patterns over time periods\n")
 			fmt.Fprintf(os.Stderr, "- analyze_failure_correlation: Analyze failures across multiple jobs to identify systemic issues\n")
-			fmt.Fprintf(os.Stderr, "- analyze_quarantine_intelligence: Provide intelligent analysis of quarantined tests and recommendations\n")
+			fmt.Fprintf(os.Stderr, "- analyze_quarantine_intelligence: Compare quarantined tests in quarantine and regular lanes and recommend (un-)quarantining\n")
 			fmt.Fprintf(os.Stderr, "- assess_failure_impact: Assess the impact and priority of test failures for triage\n")
 			fmt.Fprintf(os.Stderr, "- generate_failure_report: Generate comprehensive failure analysis report for stakeholders\n")
+			fmt.Fprintf(os.Stderr, "- get_test_history: Show where tests matching a name regex ran across all lanes\n")
 			fmt.Fprintf(os.Stderr, "\n")
 		}
 		
-		// Start the server
-		if err := server.Serve(); err != nil {
-			return fmt.Errorf("MCP server failed: %w", err)
+		// Serve over HTTP when a port is given, unless stdio was requested explicitly
+		if mcpPort == 0 || (cmd.Flags().Changed("stdio") && mcpStdio) {
+			if !mcpStdio {
+				return fmt.Errorf("no transport selected: use --stdio or --port")
+			}
+			if err := server.Serve(); err != nil {
+				return fmt.Errorf("MCP server failed: %w", err)
+			}
+			return nil
 		}
-		
-		return nil
+
+		// The environment keeps the token out of the process list
+		if mcpToken == "" {
+			mcpToken = os.Getenv("HEALTHCHECK_MCP_TOKEN")
+		}
+		addr := net.JoinHostPort(mcpHost, strconv.Itoa(mcpPort))
+		fmt.Fprintf(os.Stderr, "Serving MCP on http://%s (streamable HTTP at %s, SSE at %s)\n",
+			addr, mcp.StreamableHTTPPath, mcp.SSEPath)
+		if mcpToken == "" {
+			fmt.Fprintf(os.Stderr, "Warning: no --token set, every client reaching %s is accepted\n", addr)
+		}
+		return server.ListenAndServe(cmd.Context(), mcp.HTTPConfig{
+			Addr:            addr,
+			Token:           mcpToken,
+			ShutdownTimeout: mcpShutdownTimeout,
+		})
 	},
 }
 
 func init() {
-	mcpCmd.Flags().IntVarP(&mcpPort, "port", "p", 0, "Port to listen on (0 for stdio)")
-	mcpCmd.Flags().StringVarP(&mcpHost, "host", "H", "localhost", "Host to bind to")
+	mcpCmd.Flags().IntVarP(&mcpPort, "port", "p", 0, "Port to serve HTTP on (0 for stdio)")
+	mcpCmd.Flags().StringVarP(&mcpHost, "host", "H", "localhost", "Host to bind the HTTP server to")
 	mcpCmd.Flags().BoolVarP(&mcpStdio, "stdio", "s", true, "Use stdio transport (default)")
-	mcpCmd.Flags().BoolVarP(&mcpDebug, "debug", "d", false, "Enable debug logging")
+	mcpCmd.Flags().StringVar(&mcpToken, "token", "", "Bearer token HTTP clients must send (default: $HEALTHCHECK_MCP_TOKEN)")
+	mcpCmd.Flags().DurationVar(&mcpShutdownTimeout, "shutdown-timeout", 10*time.Second, "How long open HTTP requests may run after Ctrl-C before they are closed")
+	mcpCmd.MarkFlagsMutuallyExclusive("stdio", "port")
 
 	rootCmd.AddCommand(mcpCmd)
 }
\ No newline at end of file
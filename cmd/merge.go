@@ -3,7 +3,9 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 
 	"healthcheck/pkg/healthcheck"
 
@@ -12,6 +14,8 @@ import (
 
 var (
 	testRegex            string
+	mergeLabels          []string
+	mergeGroupBy         string
 	countFailures        bool
 	displayOnlyURLs      bool
 	displayOnlyTestNames bool
@@ -21,13 +25,14 @@ var (
 	sincePeriod          string
 	outputFormat         string
 	summary              bool
+	mergeFromDB          bool
 )
 
 var mergeCmd = &cobra.Command{
 	Use:   "merge [job-name-or-alias]",
 	Short: "Parse KubeVirt CI health data and report failed tests",
 	Args:  cobra.ExactArgs(1),
-	RunE: func(_ *cobra.Command, args []string) error {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		jobName := args[0]
 
 		// Parse time period if provided
@@ -37,9 +42,7 @@ var mergeCmd = &cobra.Command{
 		}
 
 		// Resolve job regex aliases
-		if _, ok := healthcheck.JobRegexAliases[jobName]; ok {
-			jobName = healthcheck.JobRegexAliases[jobName]
-		}
+		jobName = healthcheck.ResolveJobAlias(jobName)
 
 		// Compile regexes
 		jobRegexCompiled, err := regexp.Compile(jobName)
@@ -52,16 +55,42 @@ var mergeCmd = &cobra.Command{
 			return fmt.Errorf("invalid test name regex provided: %w", err)
 		}
 
-		// Fetch CI health data
-		results, err := healthcheck.FetchResults(healthcheck.HealthURL)
+		bySignature, err := groupBySignature(mergeGroupBy)
 		if err != nil {
 			return err
 		}
 
+		// Fetch CI health data, or build it from the history database
+		var source healthcheck.Source
+		var results *healthcheck.Results
+		if mergeFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			results, err = db.Results(cmd.Context(), timePeriod)
+			if err != nil {
+				return err
+			}
+			source = db
+		} else {
+			results, err = healthcheck.FetchResults(cmd.Context(), prowConfig.HealthURL())
+			if err != nil {
+				return err
+			}
+			source = newSource()
+		}
+
 		// Configure processor 
 		config := healthcheck.ProcessorConfig{
+			Source:               source,
+			QuarantinedTestsURL:  prowConfig.QuarantinedTestsURL(),
+			QuarantineSource:     quarantineSource,
 			JobRegex:             jobRegexCompiled,
 			TestRegex:            testRegexCompiled,
+			Labels:               mergeLabels,
 			DisplayOnlyURLs:      displayOnlyURLs,
 			DisplayOnlyTestNames: displayOnlyTestNames,
 			DisplayFailures:      displayFailures,
@@ -69,21 +98,24 @@ var mergeCmd = &cobra.Command{
 			GroupByLaneRun:       groupByLaneRun,
 			CheckQuarantine:      checkQuarantine,
 			TimePeriod:           timePeriod,
-			SuppressOutput:       outputFormat == "json", // Suppress output for JSON formatting
+			SuppressOutput:       outputFormat == "json" || bySignature, // Suppress output for JSON formatting or clustering
 			Summary:              summary,
+			Parallel:             parallel,
 		}
 
 		// Process failures
-		result, err := healthcheck.ProcessFailures(results, config)
+		result, err := healthcheck.ProcessFailures(cmd.Context(), results, config)
 		if err != nil {
 			return err
 		}
 
 		// Output results
 		if outputFormat == "json" {
-			return outputMergeJSON(result, config)
+			return outputMergeJSON(result, config, bySignature)
 		} else {
-			if summary {
+			if bySignature {
+				healthcheck.FormatSignatureClusters(healthcheck.ClusterFailures(mergedFailures(result)), displayFailures)
+			} else if summary {
 				healthcheck.FormatMergeSummary(result)
 			} else if groupByLaneRun {
 				healthcheck.FormatLaneRunOutput(result.LaneRunFailures, displayFailures)
@@ -100,6 +132,7 @@ var mergeCmd = &cobra.Command{
 
 func init() {
 	mergeCmd.Flags().StringVarP(&testRegex, "test", "t", "", "Test name regex")
+	mergeCmd.Flags().StringSliceVar(&mergeLabels, "label", nil, "Only include tests carrying one of these Ginkgo labels (e.g., sig-compute)")
 	mergeCmd.Flags().BoolVarP(&countFailures, "count", "c", false, "Count specific test failures")
 	mergeCmd.Flags().BoolVarP(&displayOnlyURLs, "url", "u", false, "Display only failed job URLs")
 	mergeCmd.Flags().BoolVarP(&displayOnlyTestNames, "name", "n", false, "Display only failed test names")
@@ -109,15 +142,23 @@ func init() {
 	mergeCmd.Flags().StringVarP(&sincePeriod, "since", "s", "", "Limit results to given time period (e.g., 24h, 2d, 1w)")
 	mergeCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text or json")
 	mergeCmd.Flags().BoolVar(&summary, "summary", false, "Display a concise summary of failures and patterns")
+	mergeCmd.Flags().StringVar(&mergeGroupBy, "group-by", "test", "Group failures by test name or by normalized failure message: test or signature")
+	mergeCmd.MarkFlagsMutuallyExclusive("group-by", "lane-run")
+	mergeCmd.Flags().BoolVar(&mergeFromDB, "from-db", false, "Answer from the local history database instead of ci-health and Prow")
 
 	rootCmd.AddCommand(mergeCmd)
 }
 
 // outputMergeJSON outputs merge data in JSON format
-func outputMergeJSON(result *healthcheck.ProcessorResult, config healthcheck.ProcessorConfig) error {
+func outputMergeJSON(result *healthcheck.ProcessorResult, config healthcheck.ProcessorConfig, bySignature bool) error {
 	var output interface{}
 
-	if config.DisplayOnlyURLs {
+	if bySignature {
+		// Failures clustered by signature, with the affected tests of each cluster
+		output = map[string]interface{}{
+			"signature_clusters": healthcheck.ClusterFailures(mergedFailures(result)),
+		}
+	} else if config.DisplayOnlyURLs {
 		// Extract URLs from all test cases
 		var urls []string
 		for _, testcases := range result.FailedTests {
@@ -176,4 +217,13 @@ func outputMergeJSON(result *healthcheck.ProcessorResult, config healthcheck.Pro
 
 	fmt.Println(string(jsonBytes))
 	return nil
-}
\ No newline at end of file
+}
+
+// mergedFailures returns all failed testcases of a merge result, ordered by test name
+func mergedFailures(result *healthcheck.ProcessorResult) []healthcheck.Testcase {
+	var failures []healthcheck.Testcase
+	for _, testName := range slices.Sorted(maps.Keys(result.FailedTests)) {
+		failures = append(failures, result.FailedTests[testName]...)
+	}
+	return failures
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	prSincePeriod  string
+	prCompareLimit int
+	prDisplayURLs  bool
+	prOutputFormat string
+	prFromDB       bool
+)
+
+var prCmd = &cobra.Command{
+	Use:   "pr [number]",
+	Short: "Show the CI history of a pull request across all lanes",
+	Long: `Find every run of a pull request under pr-logs/pull/<org>_<repo>/<number>/, show the
+attempts of each lane with the tests that failed, and check whether those tests also
+failed on other pull requests or in the periodic counterpart of the lane within --since.
+Tests failing elsewhere too are most likely not caused by the pull request.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(args[0])
+		if err != nil || number <= 0 {
+			return fmt.Errorf("invalid pull request number: %s", args[0])
+		}
+
+		timePeriod, err := healthcheck.ParseTimePeriod(prSincePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid time period: %w", err)
+		}
+
+		source := newSource()
+		if prFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			source = db
+		}
+
+		config := healthcheck.PullRequestConfig{
+			TimePeriod:   timePeriod,
+			CompareLimit: prCompareLimit,
+			Parallel:     parallel,
+		}
+		report, err := healthcheck.AnalyzePullRequest(cmd.Context(), source, number, config)
+		if err != nil {
+			return err
+		}
+
+		if prOutputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON output: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+
+		healthcheck.FormatPullRequestReport(report, prDisplayURLs)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+
+	prCmd.Flags().StringVarP(&prSincePeriod, "since", "s", "1w", "Window in which failures on other PRs and periodics are looked up (e.g., 24h, 2d, 1w)")
+	prCmd.Flags().IntVarP(&prCompareLimit, "limit", "l", 50, "Maximum number of other runs compared per lane")
+	prCmd.Flags().BoolVarP(&prDisplayURLs, "url", "u", false, "List the runs in which a failed test also failed elsewhere")
+	prCmd.Flags().StringVarP(&prOutputFormat, "output", "o", "text", "Output format: text or json")
+	prCmd.Flags().BoolVar(&prFromDB, "from-db", false, "Answer from the local history database instead of Prow")
+}
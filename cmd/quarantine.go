@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	quarantineDiff         bool
+	quarantineOutputFormat string
+)
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "List quarantined tests from the published report or a KubeVirt checkout",
+	Long: `List the quarantined tests with their SIG, test_id and quarantine date. They are read
+from the published quarantined tests report, or with --quarantine-source from the
+[QUARANTINE] labels and decorators in the Go sources of a KubeVirt checkout.
+
+A test is only quarantined once its label is merged, and the report is published
+later. With --diff the quarantined tests of the checkout are compared with the
+report, listing pending quarantines not yet in the report and lifted quarantines
+the report still lists.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if quarantineDiff && quarantineSource == "" {
+			return fmt.Errorf("--diff requires --quarantine-source")
+		}
+
+		entries, err := healthcheck.LoadQuarantinedTests(cmd.Context(), prowConfig.QuarantinedTestsURL(), quarantineSource)
+		if err != nil {
+			return err
+		}
+
+		var diff *healthcheck.QuarantineDiff
+		if quarantineDiff {
+			published, err := healthcheck.FetchQuarantinedTests(cmd.Context(), prowConfig.QuarantinedTestsURL())
+			if err != nil {
+				return err
+			}
+			diff = healthcheck.DiffQuarantine(entries, published)
+		}
+
+		if quarantineOutputFormat == "json" {
+			output := map[string]interface{}{"quarantined": entries}
+			if diff != nil {
+				output["diff"] = diff
+			}
+			jsonBytes, err := json.MarshalIndent(output, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON output: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+
+		title := "Quarantined tests (report)"
+		if quarantineSource != "" {
+			title = fmt.Sprintf("Quarantined tests (%s)", quarantineSource)
+		}
+		healthcheck.FormatQuarantineEntries(title, entries)
+		if diff != nil {
+			fmt.Println()
+			healthcheck.FormatQuarantineDiff(diff)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quarantineCmd)
+
+	quarantineCmd.Flags().BoolVar(&quarantineDiff, "diff", false, "Compare the quarantined tests of --quarantine-source with the published report")
+	quarantineCmd.Flags().StringVarP(&quarantineOutputFormat, "output", "o", "text", "Output format: text or json")
+}
@@ -1,19 +1,173 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+
+	"healthcheck/pkg/healthcheck"
 
 	"github.com/spf13/cobra"
 )
 
+// prowConfig holds the Prow deployment used by all commands
+var prowConfig = healthcheck.DefaultProwConfig()
+
+// parallel is the number of job runs fetched concurrently
+var parallel int
+
+// httpConfig configures the HTTP client shared by all fetchers
+var httpConfig = healthcheck.DefaultHTTPClientConfig()
+
+// debug enables diagnostic output on stderr
+var debug bool
+
+var (
+	noCache     bool
+	cacheDirArg string
+	dbPath      string
+	recordDir   string
+	replayDir   string
+	// quarantineSource is a KubeVirt checkout whose sources define the quarantined tests
+	quarantineSource string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "healthcheck",
 	Short: "Parse KubeVirt CI health data and report failed tests",
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		if err := loadConfig(cmd); err != nil {
+			return err
+		}
+		return configureHTTPClient()
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&prowConfig.ProwURL, "prow-url", prowConfig.ProwURL, "Base URL of the Prow deployment")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.GCSWebURL, "gcsweb-url", prowConfig.GCSWebURL, "Base URL of the gcsweb frontend used for directory listings")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.StorageURL, "storage-url", prowConfig.StorageURL, "Base URL of the object storage serving job artifacts")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.Bucket, "bucket", prowConfig.Bucket, "Bucket holding job artifacts")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.Org, "org", prowConfig.Org, "GitHub organization of the tested repository")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.Repo, "repo", prowConfig.Repo, "GitHub repository of the tested repository")
+	rootCmd.PersistentFlags().StringVar(&prowConfig.CIHealthURL, "ci-health-url", prowConfig.CIHealthURL, "Base URL of the ci-health output")
+	rootCmd.PersistentFlags().IntVar(&httpConfig.MaxRetries, "retries", httpConfig.MaxRetries, "Number of retries of requests failing with 5xx, 429 or connection errors")
+	rootCmd.PersistentFlags().Float64Var(&httpConfig.RequestsPerSecond, "rate-limit", httpConfig.RequestsPerSecond, "Maximum number of HTTP requests per second (0 for unlimited)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Always download artifacts instead of using the on-disk cache")
+	rootCmd.PersistentFlags().StringVar(&cacheDirArg, "cache-dir", "", "Directory of the artifact cache (default: $XDG_CACHE_HOME/healthcheck)")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Path of the history database (default: $XDG_DATA_HOME/healthcheck/history.db)")
+	rootCmd.PersistentFlags().StringVar(&recordDir, "record", "", "Save every HTTP response to this directory for later --replay")
+	rootCmd.PersistentFlags().StringVar(&replayDir, "replay", "", "Serve HTTP responses saved by --record from this directory instead of the network")
+	rootCmd.MarkFlagsMutuallyExclusive("record", "replay")
+	rootCmd.PersistentFlags().StringVar(&quarantineSource, "quarantine-source", "", "KubeVirt checkout scanned for [QUARANTINE] tests instead of fetching the quarantined tests report")
+	rootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
+	rootCmd.PersistentFlags().IntVar(&parallel, "parallel", healthcheck.DefaultParallelism, "Number of job runs whose artifacts are fetched concurrently")
+}
+
+// configureHTTPClient sets up the shared HTTP client, recording or replaying responses if requested
+func configureHTTPClient() error {
+	config := httpConfig
+
+	switch {
+	case recordDir != "":
+		transport, err := healthcheck.NewRecordingTransport(recordDir, nil)
+		if err != nil {
+			return err
+		}
+		config.Transport = transport
+		// Every response must reach the recording, so bypass the artifact cache
+		noCache = true
+	case replayDir != "":
+		transport, err := healthcheck.NewReplayTransport(replayDir)
+		if err != nil {
+			return err
+		}
+		config.Transport = transport
+		// Recorded responses are served locally and exercise the full fetch path
+		config.RequestsPerSecond = 0
+		noCache = true
+
+		// Time periods such as --since select the runs they selected when recording
+		capturedAt, err := healthcheck.RecordingTime(replayDir)
+		if err != nil {
+			return err
+		}
+		if !capturedAt.IsZero() {
+			healthcheck.SetNow(capturedAt)
+		}
+	}
+
+	healthcheck.ConfigureHTTPClient(config)
+	return nil
+}
+
+// newSource creates the CI data source configured by the global flags
+func newSource() healthcheck.Source {
+	return healthcheck.NewProwSource(prowConfig, newCache())
+}
+
+// openHistoryDB opens the history database configured by the global flags
+func openHistoryDB() (*healthcheck.HistoryDB, error) {
+	path := dbPath
+	if path == "" {
+		var err error
+		if path, err = healthcheck.DefaultHistoryDBPath(); err != nil {
+			return nil, err
+		}
+	}
+	return healthcheck.OpenHistoryDB(path, prowConfig)
+}
+
+// groupBySignature validates a --group-by value and reports whether failures are clustered by signature
+func groupBySignature(groupBy string) (bool, error) {
+	switch groupBy {
+	case "test":
+		return false, nil
+	case "signature":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid --group-by value %q (expected test or signature)", groupBy)
+	}
+}
+
+// cacheDir returns the artifact cache directory configured by the global flags
+func cacheDir() (string, error) {
+	if cacheDirArg != "" {
+		return cacheDirArg, nil
+	}
+	return healthcheck.DefaultCacheDir()
+}
+
+// newCache creates the artifact cache, or returns nil when caching is disabled or unavailable
+func newCache() *healthcheck.Cache {
+	if noCache {
+		return nil
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		if debug {
+			fmt.Fprintf(os.Stderr, "Artifact cache disabled: %v\n", err)
+		}
+		return nil
+	}
+	return healthcheck.NewCache(dir)
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel outstanding requests on Ctrl-C instead of waiting for their timeouts
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+
+	if debug {
+		stats := healthcheck.HTTPClientStats()
+		fmt.Fprintf(os.Stderr, "HTTP requests: %d, retries: %d, throttled: %d\n",
+			stats.Requests, stats.Retries, stats.Throttled)
+	}
+
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncSincePeriod string
+	syncLimit       int
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [job-name...]",
+	Short: "Store finished job runs in the local history database",
+	Long: `Incrementally store finished job runs and their testcases in the local history database.
+
+Each lane resumes from the highest build ID it stored, or from the oldest run that was
+still pending, so repeated syncs only fetch new runs.
+Lanes without stored runs look back --since. Without job names, every lane listed by
+ci-health and every lane already in the database is synced.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		initialPeriod, err := healthcheck.ParseTimePeriod(syncSincePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid time period: %w", err)
+		}
+
+		db, err := openHistoryDB()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		lanes := args
+		if len(lanes) == 0 {
+			if lanes, err = syncLanes(cmd, db); err != nil {
+				return err
+			}
+		}
+
+		source := newSource()
+		total := 0
+		failedLanes := 0
+		for _, lane := range lanes {
+			synced, err := healthcheck.SyncLane(ctx, source, db, lane, initialPeriod, syncLimit, parallel)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Keep syncing the remaining lanes
+				fmt.Fprintf(os.Stderr, "Warning: failed to sync %s: %v\n", lane, err)
+				failedLanes++
+				continue
+			}
+			fmt.Printf("%s: stored %d new runs\n", lane, synced)
+			total += synced
+		}
+
+		fmt.Printf("\nStored %d new runs of %d lanes\n", total, len(lanes)-failedLanes)
+		if failedLanes > 0 {
+			return fmt.Errorf("failed to sync %d lanes", failedLanes)
+		}
+		return nil
+	},
+}
+
+// syncLanes returns the lanes listed by ci-health together with the lanes already in the database
+func syncLanes(cmd *cobra.Command, db *healthcheck.HistoryDB) ([]string, error) {
+	seen := make(map[string]bool)
+
+	results, err := healthcheck.FetchResults(cmd.Context(), prowConfig.HealthURL())
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range results.Data.SIGRetests.FailedJobLeaderBoard {
+		seen[job.JobName] = true
+	}
+
+	stored, err := db.Lanes(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	for _, lane := range stored {
+		seen[lane] = true
+	}
+
+	lanes := make([]string, 0, len(seen))
+	for lane := range seen {
+		lanes = append(lanes, lane)
+	}
+	sort.Strings(lanes)
+	return lanes, nil
+}
+
+func init() {
+	syncCmd.Flags().StringVarP(&syncSincePeriod, "since", "s", "1w", "How far back to look for lanes without stored runs (e.g., 24h, 2d, 1w)")
+	syncCmd.Flags().IntVarP(&syncLimit, "limit", "l", 1000, "Maximum number of runs stored per lane, later runs are stored by the next sync")
+
+	rootCmd.AddCommand(syncCmd)
+}
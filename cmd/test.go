@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"healthcheck/pkg/healthcheck"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	testLanes          string
+	testSincePeriod    string
+	testLimit          int
+	testDisplayFailure bool
+	testOutputFormat   string
+	testFromDB         bool
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [name-regex]",
+	Short: "Show the history of a test across all lanes",
+	Long: `Report every lane where a test matching the regex ran within --since, with its pass
+and fail counts per lane, the first and last failure, and the distribution of its failure
+signatures across lanes. The lanes of the repository that ran within --since are listed
+from the artifact bucket, or from the history database with --from-db, and can be
+narrowed down with --lanes.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		testRegex, err := regexp.Compile(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid test name regex provided: %w", err)
+		}
+
+		// Resolve job regex aliases
+		laneRegex, err := regexp.Compile(healthcheck.ResolveJobAlias(testLanes))
+		if err != nil {
+			return fmt.Errorf("invalid lane regex provided: %w", err)
+		}
+
+		timePeriod, err := healthcheck.ParseTimePeriod(testSincePeriod)
+		if err != nil {
+			return fmt.Errorf("invalid time period: %w", err)
+		}
+
+		source := newSource()
+		if testFromDB {
+			db, err := openHistoryDB()
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			source = db
+		}
+
+		lanes, err := healthcheck.ActiveLanes(ctx, source, prowConfig.HealthURL(), laneRegex, timePeriod)
+		if err != nil {
+			return err
+		}
+		if len(lanes) == 0 {
+			return fmt.Errorf("no lanes match %s", testLanes)
+		}
+
+		config := healthcheck.TestHistoryConfig{
+			TestRegex:  testRegex,
+			TimePeriod: timePeriod,
+			RunLimit:   testLimit,
+			Parallel:   parallel,
+		}
+		history, err := healthcheck.AnalyzeTestHistory(ctx, source, lanes, config)
+		if err != nil {
+			return err
+		}
+
+		if testOutputFormat == "json" {
+			jsonBytes, err := json.MarshalIndent(history, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON output: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+
+		healthcheck.FormatTestHistory(history, testDisplayFailure)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+
+	testCmd.Flags().StringVar(&testLanes, "lanes", ".*", "Only search lanes matching this regex or alias (e.g., compute, arm64)")
+	testCmd.Flags().StringVarP(&testSincePeriod, "since", "s", "1w", "Analyze runs within time period (e.g., 24h, 2d, 1w)")
+	testCmd.Flags().IntVarP(&testLimit, "limit", "l", 100, "Maximum number of runs analyzed per lane")
+	testCmd.Flags().BoolVarP(&testDisplayFailure, "failures", "f", false, "Print a sample failure message of each signature")
+	testCmd.Flags().StringVarP(&testOutputFormat, "output", "o", "text", "Output format: text or json")
+	testCmd.Flags().BoolVar(&testFromDB, "from-db", false, "Answer from the local history database instead of Prow")
+}
@@ -0,0 +1,263 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// HTTPClientConfig configures the HTTP client shared by all fetchers
+type HTTPClientConfig struct {
+	Timeout           time.Duration     // Timeout of a single attempt, including reading the body
+	MaxRetries        int               // Retries of a request failing with a transient error
+	MinBackoff        time.Duration     // Backoff before the first retry, doubled on every further retry
+	MaxBackoff        time.Duration     // Upper bound of the backoff between retries
+	RequestsPerSecond float64           // Global request rate limit, 0 disables limiting
+	Transport         http.RoundTripper // Underlying transport, http.DefaultTransport when nil
+}
+
+// DefaultHTTPClientConfig returns the HTTP client configuration used when none is configured
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:           60 * time.Second,
+		MaxRetries:        3,
+		MinBackoff:        500 * time.Millisecond,
+		MaxBackoff:        10 * time.Second,
+		RequestsPerSecond: 20,
+	}
+}
+
+// HTTPStats counts the requests made through the shared HTTP client
+type HTTPStats struct {
+	Requests  int64 // Attempts sent, including retries
+	Retries   int64 // Attempts repeated after a transient error
+	Throttled int64 // Requests delayed by the rate limit or a 429 response
+}
+
+var (
+	httpClientMu sync.RWMutex
+	httpClient   *http.Client
+	httpStats    *retryTransport
+)
+
+func init() {
+	ConfigureHTTPClient(DefaultHTTPClientConfig())
+}
+
+// ConfigureHTTPClient replaces the HTTP client shared by all fetchers and resets its statistics
+func ConfigureHTTPClient(config HTTPClientConfig) {
+	transport := newRetryTransport(config)
+
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+	httpClient = &http.Client{Transport: transport}
+	httpStats = transport
+}
+
+// HTTPClient returns the HTTP client shared by all fetchers
+func HTTPClient() *http.Client {
+	httpClientMu.RLock()
+	defer httpClientMu.RUnlock()
+	return httpClient
+}
+
+// HTTPClientStats returns the request, retry and throttle counts of the shared HTTP client
+func HTTPClientStats() HTTPStats {
+	httpClientMu.RLock()
+	defer httpClientMu.RUnlock()
+	return HTTPStats{
+		Requests:  httpStats.requests.Load(),
+		Retries:   httpStats.retries.Load(),
+		Throttled: httpStats.throttled.Load(),
+	}
+}
+
+// retryTransport retries transient failures with jittered exponential backoff
+// and limits the global request rate
+type retryTransport struct {
+	next    http.RoundTripper
+	config  HTTPClientConfig
+	limiter *rateLimiter
+
+	requests  atomic.Int64
+	retries   atomic.Int64
+	throttled atomic.Int64
+}
+
+func newRetryTransport(config HTTPClientConfig) *retryTransport {
+	next := config.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &retryTransport{
+		next:    next,
+		config:  config,
+		limiter: newRateLimiter(config.RequestsPerSecond),
+	}
+}
+
+// RoundTrip sends the request, retrying 5xx and 429 responses and connection errors.
+// Requests with a body are never retried.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		throttled, err := t.limiter.wait(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if throttled {
+			t.throttled.Add(1)
+		}
+
+		resp, err := t.attempt(req)
+		t.requests.Add(1)
+
+		if attempt >= t.config.MaxRetries || req.Body != nil || !isTransient(resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		backoff := t.backoff(attempt)
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				t.throttled.Add(1)
+			}
+			if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > backoff {
+				backoff = min(retryAfter, t.config.MaxBackoff)
+			}
+			// Drain the body so the connection can be reused
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t.retries.Add(1)
+		if err := sleep(ctx, backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends a single attempt bounded by the configured timeout. The timeout
+// keeps running while the caller reads the body and is released when it closes it.
+func (t *retryTransport) attempt(req *http.Request) (*http.Response, error) {
+	if t.config.Timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.config.Timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the jittered delay before retry number attempt+1
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.config.MinBackoff << attempt
+	if delay <= 0 || delay > t.config.MaxBackoff {
+		delay = t.config.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// Full jitter between half and the whole delay spreads retries of concurrent workers
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isTransient reports whether a request failed in a way that is worth retrying
+func isTransient(resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+		var netErr net.Error
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, syscall.EPIPE) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF) ||
+			errors.Is(err, context.DeadlineExceeded) ||
+			(errors.As(err, &netErr) && netErr.Timeout())
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// sleep waits for d or until ctx is cancelled
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnClose releases the per-attempt timeout once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// rateLimiter spaces requests evenly to stay below a global rate
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / requestsPerSecond)}
+}
+
+// wait blocks until the next request may be sent and reports whether it had to wait
+func (l *rateLimiter) wait(ctx context.Context) (bool, error) {
+	if l.interval == 0 {
+		return false, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return false, nil
+	}
+	return true, sleep(ctx, delay)
+}
@@ -0,0 +1,139 @@
+package healthcheck
+
+import (
+	"context"
+	"sort"
+)
+
+// comparisonTopFailures is the number of most common failures listed per compared lane
+const comparisonTopFailures = 3
+
+// LaneComparison compares the health of several lanes, typically the same suite on
+// different Kubernetes versions or architectures
+type LaneComparison struct {
+	Lanes          []LaneComparisonRow `json:"lanes"`
+	UniqueFailures []UniqueFailure     `json:"unique_failures"` // Tests failing in only one lane, those passing elsewhere first
+}
+
+// LaneComparisonRow is the health of one compared lane
+type LaneComparisonRow struct {
+	JobName                   string               `json:"job_name"`
+	TotalRuns                 int                  `json:"total_runs"`
+	FailedRuns                int                  `json:"failed_runs"`
+	FailureRate               float64              `json:"failure_rate"`
+	InfrastructureFailureRate float64              `json:"infrastructure_failure_rate"`
+	TopFailures               []TestFailurePattern `json:"top_failures"`
+	Error                     string               `json:"error,omitempty"` // Why the lane could not be analyzed
+}
+
+// UniqueFailure is a test that failed in a single compared lane. When it passed in
+// other lanes, the failure points to a problem specific to the lane's environment.
+type UniqueFailure struct {
+	TestName string   `json:"test_name"`
+	JobName  string   `json:"job_name"`
+	Failures int      `json:"failures"`
+	PassedIn []string `json:"passed_in,omitempty"`  // Other lanes in which the test ran without failing
+	NotRunIn []string `json:"not_run_in,omitempty"` // Other lanes in which the test did not run
+}
+
+// FetchLaneSummaries calls fetch for every lane and returns the summaries and errors in
+// the order of lanes. The parallel budget is shared: lanes are fetched concurrently, and
+// each fetch is given its share to fetch the lane's runs with, so that no more than
+// parallel runs are fetched at a time.
+func FetchLaneSummaries(ctx context.Context, lanes []string, parallel int, fetch func(ctx context.Context, jobName string, parallel int) (*LaneSummary, error)) ([]*LaneSummary, []error) {
+	if parallel < 1 {
+		parallel = DefaultParallelism
+	}
+	concurrentLanes := min(parallel, max(len(lanes), 1))
+	perLane := max(1, parallel/concurrentLanes)
+
+	summaries := make([]*LaneSummary, len(lanes))
+	errs := make([]error, len(lanes))
+	runParallel(len(lanes), concurrentLanes, func(i int) {
+		summaries[i], errs[i] = fetch(ctx, lanes[i], perLane)
+	})
+	return summaries, errs
+}
+
+// CompareLanes compares the summaries of lanes, as returned by FetchLaneSummaries. Lanes
+// whose summary could not be fetched are listed with their error.
+func CompareLanes(lanes []string, summaries []*LaneSummary, errs []error) *LaneComparison {
+	comparison := &LaneComparison{
+		Lanes:          make([]LaneComparisonRow, 0, len(lanes)),
+		UniqueFailures: []UniqueFailure{},
+	}
+
+	failedIn := make(map[string][]int)             // Indexes of the lanes each test failed in
+	failures := make([]map[string]int, len(lanes)) // Failed testcases of each lane by test name
+	for i, lane := range lanes {
+		row := LaneComparisonRow{JobName: lane, TopFailures: []TestFailurePattern{}}
+		if errs[i] != nil {
+			row.Error = errs[i].Error()
+			comparison.Lanes = append(comparison.Lanes, row)
+			continue
+		}
+
+		summary := summaries[i]
+		row.TotalRuns = summary.TotalRuns
+		row.FailedRuns = summary.FailedRuns
+		row.FailureRate = summary.FailureRate
+		row.InfrastructureFailureRate = summary.InfrastructureFailureRate
+		for j, pattern := range summary.TopFailures {
+			if j == comparisonTopFailures {
+				break
+			}
+			row.TopFailures = append(row.TopFailures, pattern)
+		}
+		comparison.Lanes = append(comparison.Lanes, row)
+
+		failures[i] = testFailureCounts(summary)
+		for name := range failures[i] {
+			failedIn[name] = append(failedIn[name], i)
+		}
+	}
+
+	for name, indexes := range failedIn {
+		if len(indexes) != 1 {
+			continue
+		}
+		i := indexes[0]
+		unique := UniqueFailure{TestName: name, JobName: lanes[i], Failures: failures[i][name]}
+		for j, lane := range lanes {
+			if j == i || errs[j] != nil {
+				continue
+			}
+			if stats, ok := summaries[j].TestStats[name]; ok && stats.Executions > 0 {
+				unique.PassedIn = append(unique.PassedIn, lane)
+			} else {
+				unique.NotRunIn = append(unique.NotRunIn, lane)
+			}
+		}
+		comparison.UniqueFailures = append(comparison.UniqueFailures, unique)
+	}
+
+	// Tests that passed elsewhere are the strongest environment-specific signal
+	sort.Slice(comparison.UniqueFailures, func(i, j int) bool {
+		a, b := comparison.UniqueFailures[i], comparison.UniqueFailures[j]
+		if len(a.PassedIn) != len(b.PassedIn) {
+			return len(a.PassedIn) > len(b.PassedIn)
+		}
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.TestName < b.TestName
+	})
+	return comparison
+}
+
+// testFailureCounts counts the failed testcases of a lane's runs by test name. Unlike
+// LaneSummary.TestFailures it leaves out the "Infrastructure failure" placeholders of
+// runs without test results, which are not tests.
+func testFailureCounts(summary *LaneSummary) map[string]int {
+	counts := make(map[string]int)
+	for _, run := range summary.Runs {
+		for _, failure := range run.Failures {
+			counts[failure.Name]++
+		}
+	}
+	return counts
+}
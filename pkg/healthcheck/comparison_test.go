@@ -0,0 +1,73 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetchLaneSummariesSharesParallelism(t *testing.T) {
+	tests := []struct {
+		lanes, parallel, perLane int
+	}{
+		{lanes: 3, parallel: 8, perLane: 2},
+		{lanes: 1, parallel: 8, perLane: 8},
+		{lanes: 20, parallel: 4, perLane: 1},
+	}
+	for _, tt := range tests {
+		lanes := make([]string, tt.lanes)
+		var (
+			mu                  sync.Mutex
+			running, maxRunning int
+		)
+		FetchLaneSummaries(context.Background(), lanes, tt.parallel, func(_ context.Context, _ string, parallel int) (*LaneSummary, error) {
+			if parallel != tt.perLane {
+				t.Errorf("%d lanes, parallel %d: lane fetches %d runs at a time, want %d", tt.lanes, tt.parallel, parallel, tt.perLane)
+			}
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return &LaneSummary{}, nil
+		})
+		if maxRunning*tt.perLane > tt.parallel {
+			t.Errorf("%d lanes, parallel %d: %d lanes fetched at a time", tt.lanes, tt.parallel, maxRunning)
+		}
+	}
+}
+
+func TestCompareLanesSkipsInfrastructurePlaceholders(t *testing.T) {
+	aborted := "Infrastructure failure (ABORTED)"
+	summaries := []*LaneSummary{
+		{
+			TestFailures: map[string]int{"flaky": 1, aborted: 1},
+			TestStats:    map[string]TestStats{"flaky": {Executions: 1, Failures: 1}},
+			Runs: []JobRun{
+				{Status: "FAILURE", Failures: []Testcase{{Name: "flaky"}}},
+				{Status: "ABORTED"},
+			},
+		},
+		{
+			TestFailures: map[string]int{},
+			TestStats:    map[string]TestStats{"flaky": {Executions: 1, Passes: 1}},
+			Runs:         []JobRun{{Status: "SUCCESS"}},
+		},
+	}
+	comparison := CompareLanes([]string{"a", "b"}, summaries, make([]error, 2))
+
+	if len(comparison.UniqueFailures) != 1 {
+		t.Fatalf("UniqueFailures = %+v, want only flaky", comparison.UniqueFailures)
+	}
+	unique := comparison.UniqueFailures[0]
+	if unique.TestName != "flaky" || unique.JobName != "a" || unique.Failures != 1 {
+		t.Errorf("unique failure = %+v, want flaky failing once in a", unique)
+	}
+	if len(unique.PassedIn) != 1 || unique.PassedIn[0] != "b" {
+		t.Errorf("PassedIn = %v, want [b]", unique.PassedIn)
+	}
+}
@@ -0,0 +1,115 @@
+package healthcheck
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the content of the configuration file. Values set in it replace the
+// built-in defaults; flags given on the command line take precedence over both.
+type Config struct {
+	// Aliases are job regex aliases, added to or replacing the built-in JobRegexAliases
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+	// Sources are the default endpoints of the Prow deployment, keyed by global flag name
+	Sources SourcesConfig `yaml:"sources,omitempty"`
+	// Defaults are default values of global flags, keyed by flag name
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+	// Commands are default values of command flags, keyed by command and flag name
+	Commands map[string]map[string]string `yaml:"commands,omitempty"`
+}
+
+// SourcesConfig holds the endpoints of the Prow deployment and its artifact bucket
+type SourcesConfig struct {
+	ProwURL     string `yaml:"prow-url,omitempty"`
+	GCSWebURL   string `yaml:"gcsweb-url,omitempty"`
+	StorageURL  string `yaml:"storage-url,omitempty"`
+	Bucket      string `yaml:"bucket,omitempty"`
+	Org         string `yaml:"org,omitempty"`
+	Repo        string `yaml:"repo,omitempty"`
+	CIHealthURL string `yaml:"ci-health-url,omitempty"`
+}
+
+// Flags returns the configured endpoints keyed by the global flag setting them
+func (s SourcesConfig) Flags() map[string]string {
+	flags := make(map[string]string)
+	for name, value := range map[string]string{
+		"prow-url":      s.ProwURL,
+		"gcsweb-url":    s.GCSWebURL,
+		"storage-url":   s.StorageURL,
+		"bucket":        s.Bucket,
+		"org":           s.Org,
+		"repo":          s.Repo,
+		"ci-health-url": s.CIHealthURL,
+	} {
+		if value != "" {
+			flags[name] = value
+		}
+	}
+	return flags
+}
+
+// DefaultConfigPath returns the location of the configuration file,
+// $XDG_CONFIG_HOME/healthcheck/config.yaml (~/.config/healthcheck/config.yaml)
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config directory: %w", err)
+	}
+	return filepath.Join(dir, "healthcheck", "config.yaml"), nil
+}
+
+// LoadConfig reads the configuration file at path. A missing file is an error when
+// mustExist is set, otherwise LoadConfig returns nil.
+func LoadConfig(path string, mustExist bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !mustExist {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ApplyAliases adds the configured aliases to JobRegexAliases, replacing built-in
+// aliases of the same name
+func (c *Config) ApplyAliases() {
+	for name, regex := range c.Aliases {
+		JobRegexAliases[name] = regex
+	}
+}
+
+// ResolveJobAlias returns the job regex of an alias, or name itself when it is not an alias
+func ResolveJobAlias(name string) string {
+	if regex, ok := JobRegexAliases[name]; ok {
+		return regex
+	}
+	return name
+}
+
+// IsJobAlias reports whether name is a job regex alias
+func IsJobAlias(name string) bool {
+	_, ok := JobRegexAliases[name]
+	return ok
+}
+
+// JobAliasNames returns the names of all job regex aliases in sorted order
+func JobAliasNames() []string {
+	names := make([]string, 0, len(JobRegexAliases))
+	for name := range JobRegexAliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,281 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// architectures are the CPU architectures recognized in lane names; lanes naming none run on amd64
+var architectures = []string{"arm64", "s390x", "ppc64le"}
+
+// kubernetesVersionPattern matches the Kubernetes version in lane names such as pull-kubevirt-e2e-k8s-1.33-sig-compute
+var kubernetesVersionPattern = regexp.MustCompile(`k8s-(\d+\.\d+)`)
+
+// CorrelationConfig selects the runs failures are correlated across
+type CorrelationConfig struct {
+	TimePeriod time.Duration // Only runs started within this period are correlated
+	RunLimit   int           // Maximum number of runs fetched per lane
+	Parallel   int           // Number of runs fetched concurrently, shared by the lanes
+}
+
+// FailureCorrelation relates the failures of several lanes within the same window
+type FailureCorrelation struct {
+	Window       string                `json:"window"`
+	Lanes        []CorrelatedLane      `json:"lanes"`
+	Tests        []CorrelatedTest      `json:"tests"`        // Tests failing in more than one lane, most lanes first
+	Signatures   []SignatureCluster    `json:"signatures"`   // Failure signatures seen in more than one lane
+	Environments []EnvironmentFailures `json:"environments"` // Failures by architecture and Kubernetes version
+	LaneErrors   []LaneError           `json:"lane_errors"`  // Lanes left out because their runs could not be fetched
+}
+
+// LaneError is a lane whose runs could not be fetched or analyzed
+type LaneError struct {
+	JobName string `json:"job_name"`
+	Error   string `json:"error"`
+}
+
+// CorrelatedLane is the health of one correlated lane and the environment it runs in
+type CorrelatedLane struct {
+	JobName                   string   `json:"job_name"`
+	Architecture              string   `json:"architecture"`
+	KubernetesVersion         string   `json:"kubernetes_version,omitempty"`
+	TotalRuns                 int      `json:"total_runs"`
+	FailedRuns                int      `json:"failed_runs"`
+	FailureRate               float64  `json:"failure_rate"`
+	InfrastructureFailureRate float64  `json:"infrastructure_failure_rate"`
+	FailedRunURLs             []string `json:"failed_run_urls"`
+}
+
+// CorrelatedTest is a test that failed in several lanes within the window
+type CorrelatedTest struct {
+	TestName         string   `json:"test_name"`
+	FailedLanes      []string `json:"failed_lanes"`
+	RanLanes         int      `json:"ran_lanes"`         // Lanes in which the test passed or failed
+	CorrelationScore float64  `json:"correlation_score"` // Fraction of the lanes running the test in which it failed
+	Failures         int      `json:"failures"`
+	Signature        string   `json:"signature"` // Most common failure signature of the test
+	FirstFailure     string   `json:"first_failure,omitempty"`
+	LastFailure      string   `json:"last_failure,omitempty"`
+	EvidenceURLs     []string `json:"evidence_urls"` // Failed runs, one per lane first
+}
+
+// EnvironmentFailures are the runs of all lanes sharing an architecture or Kubernetes version
+type EnvironmentFailures struct {
+	Dimension   string   `json:"dimension"` // architecture or kubernetes_version
+	Value       string   `json:"value"`
+	Lanes       []string `json:"lanes"`
+	TotalRuns   int      `json:"total_runs"`
+	FailedRuns  int      `json:"failed_runs"`
+	FailureRate float64  `json:"failure_rate"`
+	Failures    int      `json:"test_failures"`
+}
+
+// LaneArchitecture returns the CPU architecture a lane runs on, judging by its name
+func LaneArchitecture(jobName string) string {
+	for _, arch := range architectures {
+		if strings.Contains(jobName, arch) {
+			return arch
+		}
+	}
+	return "amd64"
+}
+
+// LaneKubernetesVersion returns the Kubernetes version a lane runs on, or "" when its name has none
+func LaneKubernetesVersion(jobName string) string {
+	if match := kubernetesVersionPattern.FindStringSubmatch(jobName); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// CorrelateFailures fetches the runs of every lane within config.TimePeriod and finds the
+// tests and failure signatures failing in several lanes, together with the failure rates
+// per architecture and Kubernetes version. Lanes without runs in the window are left out,
+// lanes whose runs cannot be fetched are listed in LaneErrors.
+func CorrelateFailures(ctx context.Context, source Source, lanes []string, config CorrelationConfig) (*FailureCorrelation, error) {
+	summaries, errs := FetchLaneSummaries(ctx, lanes, config.Parallel, func(ctx context.Context, lane string, parallel int) (*LaneSummary, error) {
+		runs, err := FetchJobHistoryWithTimePeriod(ctx, source, lane, config.TimePeriod, config.RunLimit)
+		if errors.Is(err, ErrNoJobHistory) {
+			// The lane did not run within the window
+			return &LaneSummary{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return AnalyzeLaneRuns(ctx, source, runs, parallel)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	correlation := &FailureCorrelation{
+		Window:       formatPeriod(config.TimePeriod),
+		Lanes:        []CorrelatedLane{},
+		Tests:        []CorrelatedTest{},
+		Signatures:   []SignatureCluster{},
+		Environments: []EnvironmentFailures{},
+		LaneErrors:   []LaneError{},
+	}
+	tests := make(map[string]*CorrelatedTest)
+	var failures []Testcase
+	environments := make(map[string]*EnvironmentFailures)
+	var environmentOrder []string
+
+	for i, lane := range lanes {
+		if errs[i] != nil {
+			correlation.LaneErrors = append(correlation.LaneErrors, LaneError{JobName: lane, Error: errs[i].Error()})
+			continue
+		}
+		summary := summaries[i]
+		if summary.TotalRuns == 0 {
+			continue
+		}
+		info := CorrelatedLane{
+			JobName:                   lane,
+			Architecture:              LaneArchitecture(lane),
+			KubernetesVersion:         LaneKubernetesVersion(lane),
+			TotalRuns:                 summary.TotalRuns,
+			FailedRuns:                summary.FailedRuns,
+			FailureRate:               summary.FailureRate,
+			InfrastructureFailureRate: summary.InfrastructureFailureRate,
+			FailedRunURLs:             []string{},
+		}
+		for _, run := range summary.Runs {
+			if run.Status == "FAILURE" {
+				info.FailedRunURLs = append(info.FailedRunURLs, run.URL)
+			}
+		}
+		correlation.Lanes = append(correlation.Lanes, info)
+
+		for _, dimension := range [][2]string{{"architecture", info.Architecture}, {"kubernetes_version", info.KubernetesVersion}} {
+			if dimension[1] == "" {
+				continue
+			}
+			key := dimension[0] + "/" + dimension[1]
+			env, ok := environments[key]
+			if !ok {
+				env = &EnvironmentFailures{Dimension: dimension[0], Value: dimension[1]}
+				environments[key] = env
+				environmentOrder = append(environmentOrder, key)
+			}
+			env.Lanes = append(env.Lanes, lane)
+			env.TotalRuns += summary.TotalRuns
+			env.FailedRuns += summary.FailedRuns
+			env.Failures += len(summary.AllFailures)
+		}
+
+		for name, stats := range summary.TestStats {
+			if stats.Executions == 0 {
+				continue
+			}
+			test, ok := tests[name]
+			if !ok {
+				test = &CorrelatedTest{TestName: name}
+				tests[name] = test
+			}
+			test.RanLanes++
+		}
+
+		for _, run := range summary.Runs {
+			for _, failure := range run.Failures {
+				failure.URL = run.URL
+				failures = append(failures, failure)
+
+				test, ok := tests[failure.Name]
+				if !ok {
+					test = &CorrelatedTest{TestName: failure.Name}
+					tests[failure.Name] = test
+				}
+				test.Failures++
+				if !containsString(test.FailedLanes, lane) {
+					test.FailedLanes = append(test.FailedLanes, lane)
+				}
+				if !containsString(test.EvidenceURLs, run.URL) {
+					test.EvidenceURLs = append(test.EvidenceURLs, run.URL)
+				}
+				if run.Timestamp != "" && (test.FirstFailure == "" || run.Timestamp < test.FirstFailure) {
+					test.FirstFailure = run.Timestamp
+				}
+				if run.Timestamp > test.LastFailure {
+					test.LastFailure = run.Timestamp
+				}
+			}
+		}
+	}
+
+	for _, key := range environmentOrder {
+		env := environments[key]
+		if env.TotalRuns > 0 {
+			env.FailureRate = float64(env.FailedRuns) / float64(env.TotalRuns) * 100
+		}
+		correlation.Environments = append(correlation.Environments, *env)
+	}
+	sort.SliceStable(correlation.Environments, func(i, j int) bool {
+		a, b := correlation.Environments[i], correlation.Environments[j]
+		if a.Dimension != b.Dimension {
+			return a.Dimension < b.Dimension
+		}
+		return a.Value < b.Value
+	})
+
+	// Signatures shared by several lanes, and the most common signature of each test
+	testSignatures := make(map[string]string)
+	testSignatureCounts := make(map[string]int)
+	for _, cluster := range ClusterFailures(failures) {
+		if len(cluster.Lanes) > 1 {
+			correlation.Signatures = append(correlation.Signatures, cluster)
+		}
+		for name, count := range cluster.TestCounts {
+			if count > testSignatureCounts[name] {
+				testSignatures[name] = cluster.Signature
+				testSignatureCounts[name] = count
+			}
+		}
+	}
+
+	for name, test := range tests {
+		if len(test.FailedLanes) < 2 {
+			continue
+		}
+		if test.RanLanes < len(test.FailedLanes) {
+			test.RanLanes = len(test.FailedLanes)
+		}
+		test.CorrelationScore = float64(len(test.FailedLanes)) / float64(test.RanLanes)
+		test.Signature = testSignatures[name]
+		sort.Strings(test.FailedLanes)
+		test.EvidenceURLs = evidencePerLane(test.EvidenceURLs)
+		correlation.Tests = append(correlation.Tests, *test)
+	}
+	sort.Slice(correlation.Tests, func(i, j int) bool {
+		a, b := correlation.Tests[i], correlation.Tests[j]
+		if len(a.FailedLanes) != len(b.FailedLanes) {
+			return len(a.FailedLanes) > len(b.FailedLanes)
+		}
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.TestName < b.TestName
+	})
+
+	return correlation, nil
+}
+
+// evidencePerLane orders run URLs so that the first failed run of every lane comes first
+func evidencePerLane(urls []string) []string {
+	var first, rest []string
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		lane := jobNameFromRunURL(url)
+		if lane != "" && !seen[lane] {
+			seen[lane] = true
+			first = append(first, url)
+		} else {
+			rest = append(rest, url)
+		}
+	}
+	return append(first, rest...)
+}
@@ -0,0 +1,25 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCorrelateFailuresSkipsLanesWithoutRuns(t *testing.T) {
+	withNow(t, time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC))
+
+	lanes := []string{"pull-kubevirt-e2e-k8s-1.33-sig-compute", "pull-kubevirt-e2e-k8s-1.33-sig-compute-idle"}
+	correlation, err := CorrelateFailures(context.Background(), NewLocalSource("testdata/quarantine"), lanes,
+		CorrelationConfig{TimePeriod: 14 * 24 * time.Hour, RunLimit: 100, Parallel: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(correlation.LaneErrors) != 0 {
+		t.Errorf("LaneErrors = %+v, want none for a lane without runs", correlation.LaneErrors)
+	}
+	if len(correlation.Lanes) != 1 || correlation.Lanes[0].JobName != lanes[0] || correlation.Lanes[0].TotalRuns != 2 {
+		t.Errorf("Lanes = %+v, want only %s with 2 runs", correlation.Lanes, lanes[0])
+	}
+}
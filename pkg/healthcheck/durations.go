@@ -0,0 +1,188 @@
+package healthcheck
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DurationConfig controls how test durations are compared between time windows
+type DurationConfig struct {
+	RecentWindow        time.Duration // Runs started this long before the newest run form the recent window
+	RegressionThreshold float64       // Minimum growth of the median duration, in percent, to flag a test
+	MinIncrease         time.Duration // Minimum absolute growth of the median duration to flag a test
+	MinSamples          int           // Minimum number of executions required in each window
+}
+
+// DefaultDurationConfig compares the last day against everything before it
+var DefaultDurationConfig = DurationConfig{
+	RecentWindow:        24 * time.Hour,
+	RegressionThreshold: 50,
+	MinIncrease:         10 * time.Second,
+	MinSamples:          2,
+}
+
+// TestDurations are the duration percentiles of a single test across runs, in seconds
+type TestDurations struct {
+	TestName   string  `json:"test_name"`
+	Executions int     `json:"executions"`
+	P50        float64 `json:"p50_seconds"`
+	P90        float64 `json:"p90_seconds"`
+	Max        float64 `json:"max_seconds"`
+	EarlierP50 float64 `json:"earlier_p50_seconds,omitempty"`
+	RecentP50  float64 `json:"recent_p50_seconds,omitempty"`
+	Growth     float64 `json:"growth_percent,omitempty"` // Growth of the recent median against the earlier one
+	Regressed  bool    `json:"regressed"`
+}
+
+// RunDuration is the suite wall time of a single run
+type RunDuration struct {
+	ID        string  `json:"id"`
+	URL       string  `json:"url"`
+	Status    string  `json:"status"`
+	Timestamp string  `json:"timestamp"`
+	Seconds   float64 `json:"suite_seconds"`
+	Tests     int     `json:"tests"`
+	// Wall time of the whole job including cluster setup, from prowjob.json
+	JobSeconds float64 `json:"job_seconds,omitempty"`
+	Cluster    string  `json:"cluster,omitempty"` // Build cluster the job ran in
+}
+
+// DurationReport aggregates the test durations of a lane
+type DurationReport struct {
+	JobName     string          `json:"job_name"`
+	RecentSince string          `json:"recent_since,omitempty"` // Start of the recent window
+	Tests       []TestDurations `json:"tests"`                  // Slowest tests first, by p90
+	Regressions []TestDurations `json:"regressions"`            // Largest growth first
+	Runs        []RunDuration   `json:"runs"`                   // Newest run first
+}
+
+// AnalyzeDurations computes per-test duration percentiles over runs whose artifacts were
+// fetched, flags tests that became slower in the recent window and reports each run's
+// suite wall time. Skipped testcases and testcases without a time are ignored.
+func AnalyzeDurations(jobName string, runs []JobRun, config DurationConfig) *DurationReport {
+	report := &DurationReport{JobName: jobName}
+
+	cutoff, hasCutoff := recentCutoff(runs, config.RecentWindow)
+	if hasCutoff {
+		report.RecentSince = cutoff.Format(time.RFC3339)
+	}
+
+	all := make(map[string][]float64)
+	earlier := make(map[string][]float64)
+	recent := make(map[string][]float64)
+
+	for _, run := range runs {
+		runTime, err := time.Parse(time.RFC3339, run.Timestamp)
+		isRecent := hasCutoff && err == nil && !runTime.Before(cutoff)
+		isEarlier := hasCutoff && err == nil && runTime.Before(cutoff)
+
+		tests := 0
+		testSeconds := 0.0
+		for _, testcases := range [][]Testcase{run.Passed, run.Failures} {
+			for _, testcase := range testcases {
+				seconds, err := strconv.ParseFloat(testcase.Time, 64)
+				if err != nil {
+					continue
+				}
+				tests++
+				testSeconds += seconds
+				all[testcase.Name] = append(all[testcase.Name], seconds)
+				if isRecent {
+					recent[testcase.Name] = append(recent[testcase.Name], seconds)
+				} else if isEarlier {
+					earlier[testcase.Name] = append(earlier[testcase.Name], seconds)
+				}
+			}
+		}
+
+		if tests == 0 && run.SuiteTime == 0 {
+			continue // No test results, e.g. an infrastructure failure
+		}
+		seconds := run.SuiteTime
+		if seconds == 0 {
+			// Without a suite time attribute the tests are assumed to have run sequentially
+			seconds = testSeconds
+		}
+		runDuration := RunDuration{
+			ID:        run.ID,
+			URL:       run.URL,
+			Status:    run.Status,
+			Timestamp: run.Timestamp,
+			Seconds:   seconds,
+			Tests:     tests,
+		}
+		if run.ProwJob != nil {
+			runDuration.JobSeconds = run.ProwJob.Duration().Seconds()
+			runDuration.Cluster = run.ProwJob.Spec.Cluster
+		}
+		report.Runs = append(report.Runs, runDuration)
+	}
+
+	for name, durations := range all {
+		sort.Float64s(durations)
+		test := TestDurations{
+			TestName:   name,
+			Executions: len(durations),
+			P50:        percentile(durations, 50),
+			P90:        percentile(durations, 90),
+			Max:        durations[len(durations)-1],
+		}
+
+		if len(earlier[name]) >= config.MinSamples && len(recent[name]) >= config.MinSamples {
+			sort.Float64s(earlier[name])
+			sort.Float64s(recent[name])
+			test.EarlierP50 = percentile(earlier[name], 50)
+			test.RecentP50 = percentile(recent[name], 50)
+			if test.EarlierP50 > 0 {
+				test.Growth = (test.RecentP50 - test.EarlierP50) / test.EarlierP50 * 100
+			}
+			increase := time.Duration((test.RecentP50 - test.EarlierP50) * float64(time.Second))
+			test.Regressed = test.Growth >= config.RegressionThreshold && increase >= config.MinIncrease
+		}
+
+		report.Tests = append(report.Tests, test)
+		if test.Regressed {
+			report.Regressions = append(report.Regressions, test)
+		}
+	}
+
+	sort.Slice(report.Tests, func(i, j int) bool {
+		if report.Tests[i].P90 != report.Tests[j].P90 {
+			return report.Tests[i].P90 > report.Tests[j].P90
+		}
+		return report.Tests[i].TestName < report.Tests[j].TestName
+	})
+	sort.Slice(report.Regressions, func(i, j int) bool {
+		return report.Regressions[i].Growth > report.Regressions[j].Growth
+	})
+
+	return report
+}
+
+// recentCutoff returns the start of the recent window, measured back from the newest run
+func recentCutoff(runs []JobRun, window time.Duration) (time.Time, bool) {
+	var newest time.Time
+	for _, run := range runs {
+		if t, err := time.Parse(time.RFC3339, run.Timestamp); err == nil && t.After(newest) {
+			newest = t
+		}
+	}
+	if newest.IsZero() || window <= 0 {
+		return time.Time{}, false
+	}
+	return newest.Add(-window), true
+}
+
+// percentile returns the nearest-rank percentile p of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,100 @@
+package healthcheck
+
+import "sort"
+
+// FlakeEvidence lists the runs of one commit set in which a test both passed and failed
+type FlakeEvidence struct {
+	CommitSet  string   `json:"commit_set"`
+	PassedRuns []string `json:"passed_runs"`
+	FailedRuns []string `json:"failed_runs"`
+}
+
+// TestFlakiness describes a test that passed and failed on identical code, for example
+// after a /retest or between a batch and a presubmit run of the same pull requests
+type TestFlakiness struct {
+	TestName         string          `json:"test_name"`
+	FlakeScore       float64         `json:"flake_score"`        // Percentage of failing commit sets on which the test also passed
+	FlakyCommitSets  int             `json:"flaky_commit_sets"`  // Commit sets with both outcomes
+	FailedCommitSets int             `json:"failed_commit_sets"` // Commit sets with at least one failure
+	Evidence         []FlakeEvidence `json:"evidence"`
+}
+
+// DetectFlakes finds the tests that both passed and failed on the same commit set.
+// Runs without a known commit set are ignored, as a failure on different code may be a
+// real regression. The flake score tells how often a failure of the test was
+// contradicted by a pass on the same code; most flaky tests are listed first.
+func DetectFlakes(runs []JobRun) []TestFlakiness {
+	type outcomes struct {
+		passed, failed []string
+	}
+	// Outcomes per test and commit set, with commit sets in the order they were first seen
+	tests := make(map[string]map[string]*outcomes)
+	commitSetOrder := make(map[string]int)
+
+	for _, run := range runs {
+		if run.CommitSet == "" {
+			continue
+		}
+		if _, ok := commitSetOrder[run.CommitSet]; !ok {
+			commitSetOrder[run.CommitSet] = len(commitSetOrder)
+		}
+
+		record := func(testcases []Testcase, failed bool) {
+			for _, testcase := range testcases {
+				if tests[testcase.Name] == nil {
+					tests[testcase.Name] = make(map[string]*outcomes)
+				}
+				o := tests[testcase.Name][run.CommitSet]
+				if o == nil {
+					o = &outcomes{}
+					tests[testcase.Name][run.CommitSet] = o
+				}
+				if failed && !containsString(o.failed, run.URL) {
+					o.failed = append(o.failed, run.URL)
+				} else if !failed && !containsString(o.passed, run.URL) {
+					o.passed = append(o.passed, run.URL)
+				}
+			}
+		}
+		record(run.Passed, false)
+		record(run.Failures, true)
+	}
+
+	var flakes []TestFlakiness
+	for name, commitSets := range tests {
+		flake := TestFlakiness{TestName: name}
+		for commitSet, o := range commitSets {
+			if len(o.failed) == 0 {
+				continue
+			}
+			flake.FailedCommitSets++
+			if len(o.passed) > 0 {
+				flake.FlakyCommitSets++
+				flake.Evidence = append(flake.Evidence, FlakeEvidence{
+					CommitSet:  commitSet,
+					PassedRuns: o.passed,
+					FailedRuns: o.failed,
+				})
+			}
+		}
+		if flake.FlakyCommitSets == 0 {
+			continue
+		}
+		flake.FlakeScore = float64(flake.FlakyCommitSets) / float64(flake.FailedCommitSets) * 100
+		sort.Slice(flake.Evidence, func(i, j int) bool {
+			return commitSetOrder[flake.Evidence[i].CommitSet] < commitSetOrder[flake.Evidence[j].CommitSet]
+		})
+		flakes = append(flakes, flake)
+	}
+
+	sort.Slice(flakes, func(i, j int) bool {
+		if flakes[i].FlakyCommitSets != flakes[j].FlakyCommitSets {
+			return flakes[i].FlakyCommitSets > flakes[j].FlakyCommitSets
+		}
+		if flakes[i].FlakeScore != flakes[j].FlakeScore {
+			return flakes[i].FlakeScore > flakes[j].FlakeScore
+		}
+		return flakes[i].TestName < flakes[j].TestName
+	})
+	return flakes
+}
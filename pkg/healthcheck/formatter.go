@@ -25,6 +25,7 @@ func FormatLaneRunOutput(laneRunFailures map[string][]Testcase, displayFailures
 				fmt.Printf("\t%s\n", test.Name)
 			}
 			if displayFailures && test.Failure != nil {
+				printGinkgoDetails(test, "\t")
 				fmt.Printf("\t%s\n\n", *test.Failure)
 			}
 			fmt.Printf("\t%s\n\n", test.URL)
@@ -58,6 +59,7 @@ func FormatCountedOutput(failedTests map[string][]Testcase, displayFailures bool
 
 		for _, test := range failedTests[name] {
 			if displayFailures && test.Failure != nil {
+				printGinkgoDetails(test, "\t")
 				fmt.Printf("\t%s\n\n", *test.Failure)
 			}
 			fmt.Printf("\t%s\n\n", test.URL)
@@ -92,6 +94,18 @@ func FormatLaneOutput(jobName string, summary *LaneSummary, config LaneDisplayCo
 		return
 	}
 
+	// Handle flaky tests with their evidence runs
+	if config.Flakes {
+		FormatFlakes(summary.Flakes)
+		return
+	}
+
+	// Handle failures clustered by signature
+	if config.GroupBySignature {
+		FormatSignatureClusters(ClusterFailures(summary.AllFailures), config.DisplayFailures)
+		return
+	}
+
 	// Handle count failures output (similar to merge command)
 	if config.CountFailures {
 		// Group failures by test name like merge command does
@@ -108,16 +122,83 @@ func FormatLaneOutput(jobName string, summary *LaneSummary, config LaneDisplayCo
 	for _, failure := range summary.AllFailures {
 		fmt.Println(failure.Name)
 		if config.DisplayFailures && failure.Failure != nil {
+			printGinkgoDetails(failure, "")
 			fmt.Printf("%s\n\n", *failure.Failure)
 		}
 		fmt.Printf("%s\n\n", failure.URL)
 	}
 }
 
+// FormatSignatureClusters displays failures grouped by signature, largest cluster first,
+// with the tests and lanes each signature was seen in
+func FormatSignatureClusters(clusters []SignatureCluster, displayFailures bool) {
+	for _, cluster := range clusters {
+		fmt.Printf("%d\t%s\n", cluster.Count, cluster.Signature)
+		fmt.Printf("\t%d tests", len(cluster.Tests))
+		if len(cluster.Lanes) > 0 {
+			fmt.Printf(" in %d lanes: %s", len(cluster.Lanes), strings.Join(cluster.Lanes, ", "))
+		}
+		fmt.Printf("\n\n")
+
+		for _, test := range cluster.Tests {
+			fmt.Printf("\t%d\t%s\n", cluster.TestCounts[test], test)
+		}
+		fmt.Println()
+
+		if displayFailures && cluster.SampleMessage != "" {
+			fmt.Printf("\t%s\n\n", cluster.SampleMessage)
+		}
+		for _, url := range cluster.URLs {
+			fmt.Printf("\t%s\n", url)
+		}
+		fmt.Println("")
+	}
+}
+
+// FormatFlakes displays the tests that passed and failed on the same commit set, with the
+// passing and failing runs of every commit set as evidence
+func FormatFlakes(flakes []TestFlakiness) {
+	if len(flakes) == 0 {
+		fmt.Println("No test both passed and failed on the same commits")
+		return
+	}
+	for _, flake := range flakes {
+		fmt.Printf("%.1f%%\t%s\n", flake.FlakeScore, flake.TestName)
+		fmt.Printf("\tflaked on %d of %d commit sets it failed on\n\n", flake.FlakyCommitSets, flake.FailedCommitSets)
+		for _, evidence := range flake.Evidence {
+			fmt.Printf("\t%s\n", evidence.CommitSet)
+			for _, url := range evidence.PassedRuns {
+				fmt.Printf("\t  passed: %s\n", url)
+			}
+			for _, url := range evidence.FailedRuns {
+				fmt.Printf("\t  failed: %s\n", url)
+			}
+		}
+		fmt.Println()
+	}
+}
+
+// printGinkgoDetails prints the labels, location and failed node read from a Ginkgo report, if any
+func printGinkgoDetails(test Testcase, indent string) {
+	if len(test.Labels) > 0 {
+		fmt.Printf("%sLabels: %s\n", indent, strings.Join(test.Labels, ", "))
+	}
+	if test.Location != "" {
+		fmt.Printf("%sLocation: %s\n", indent, test.Location)
+	}
+	if test.FailureNodeType != "" {
+		fmt.Printf("%sFailed in: %s", indent, test.FailureNodeType)
+		if test.FailureLocation != "" {
+			fmt.Printf(" at %s", test.FailureLocation)
+		}
+		fmt.Println()
+	}
+}
+
 // FormatLaneSummary displays a concise summary of lane analysis
 func FormatLaneSummary(jobName string, summary *LaneSummary) {
 	fmt.Printf("Lane Summary: %s\n", jobName)
-	fmt.Printf("=" + strings.Repeat("=", len(jobName)+13) + "\n\n")
+	fmt.Printf("%s\n\n", strings.Repeat("=", len(jobName)+14))
 
 	// Time range information
 	if summary.FirstRunTime != "" && summary.LastRunTime != "" {
@@ -164,6 +245,46 @@ func FormatLaneSummary(jobName string, summary *LaneSummary) {
 	}
 	fmt.Println()
 
+	// Per-test outcomes
+	if len(summary.TestStats) > 0 {
+		executions, skips := 0, 0
+		for _, stats := range summary.TestStats {
+			executions += stats.Executions
+			skips += stats.Skips
+		}
+		fmt.Printf("Test Outcomes:\n")
+		fmt.Printf("  Unique Tests:   %d\n", len(summary.TestStats))
+		fmt.Printf("  Executions:     %d\n", executions)
+		if skips > 0 {
+			fmt.Printf("  Skipped:        %d\n", skips)
+		}
+		if lowest := lowestPassRates(summary.TestStats, 3); len(lowest) > 0 {
+			fmt.Printf("  Lowest Pass Rates:\n")
+			for _, name := range lowest {
+				stats := summary.TestStats[name]
+				fmt.Printf("    %5.1f%%  %s (failed %d of %d runs)\n",
+					stats.PassRate, truncateTestName(name, 60), stats.Failures, stats.Executions)
+			}
+		}
+		fmt.Println()
+	}
+
+	// Tests that passed and failed on identical code
+	if len(summary.Flakes) > 0 {
+		fmt.Printf("Flaky Tests (passed and failed on the same commits):\n")
+		for i, flake := range summary.Flakes {
+			if i >= 3 { // Show only top 3
+				break
+			}
+			fmt.Printf("  %5.1f%%  %s (flaked on %d of %d failing commit sets)\n",
+				flake.FlakeScore, truncateTestName(flake.TestName, 60), flake.FlakyCommitSets, flake.FailedCommitSets)
+		}
+		if len(summary.Flakes) > 3 {
+			fmt.Printf("  ... and %d more, use --flakes for the evidence runs\n", len(summary.Flakes)-3)
+		}
+		fmt.Println()
+	}
+
 	// Test failure statistics
 	if len(summary.AllFailures) > 0 {
 		fmt.Printf("Failure Analysis:\n")
@@ -199,6 +320,13 @@ func FormatLaneSummary(jobName string, summary *LaneSummary) {
 				fmt.Printf("  %d. [%s] %s (%d failures, %.1f%%)\n", 
 					i+1, pattern.Category, truncateTestName(pattern.TestName, 60), 
 					pattern.Count, pattern.Percentage)
+				if stats, ok := summary.TestStats[pattern.TestName]; ok && stats.Executions > 0 {
+					fmt.Printf("     failed %d of %d runs, %.1f%% pass rate", stats.Failures, stats.Executions, stats.PassRate)
+					if stats.Skips > 0 {
+						fmt.Printf(", skipped %d times", stats.Skips)
+					}
+					fmt.Println()
+				}
 			}
 			fmt.Println()
 		}
@@ -239,6 +367,29 @@ func FormatLaneSummary(jobName string, summary *LaneSummary) {
 	}
 }
 
+// lowestPassRates returns up to n tests that failed at least once, lowest pass rate first
+func lowestPassRates(testStats map[string]TestStats, n int) []string {
+	var names []string
+	for name, stats := range testStats {
+		if stats.Failures > 0 {
+			names = append(names, name)
+		}
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(testStats[a].PassRate, testStats[b].PassRate); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(testStats[b].Executions, testStats[a].Executions); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
+
 // truncateTestName truncates long test names for display
 func truncateTestName(name string, maxLen int) string {
 	if len(name) <= maxLen {
@@ -412,3 +563,299 @@ func FormatMergeSummary(result *ProcessorResult) {
 		}
 	}
 }
+
+// FormatDurationReport displays the slowest tests, duration regressions and suite wall
+// time per run, limiting the test lists to top entries
+func FormatDurationReport(report *DurationReport, top int) {
+	fmt.Printf("Test Durations: %s\n", report.JobName)
+	fmt.Printf("%s\n\n", strings.Repeat("=", len(report.JobName)+16))
+
+	if len(report.Tests) == 0 {
+		fmt.Printf("No test durations found\n")
+		return
+	}
+
+	fmt.Printf("Slowest Tests (by p90):\n")
+	fmt.Printf("  %9s %9s %9s %5s  %s\n", "p50", "p90", "max", "runs", "test")
+	for i, test := range report.Tests {
+		if i >= top {
+			break
+		}
+		fmt.Printf("  %9s %9s %9s %5d  %s\n", formatSeconds(test.P50), formatSeconds(test.P90),
+			formatSeconds(test.Max), test.Executions, truncateTestName(test.TestName, 80))
+	}
+	fmt.Println()
+
+	if report.RecentSince != "" {
+		fmt.Printf("Duration Regressions (since %s):\n", formatTimestamp(report.RecentSince))
+		if len(report.Regressions) == 0 {
+			fmt.Printf("  None detected\n")
+		}
+		for i, test := range report.Regressions {
+			if i >= top {
+				break
+			}
+			fmt.Printf("  %9s -> %9s (+%.0f%%)  %s\n", formatSeconds(test.EarlierP50),
+				formatSeconds(test.RecentP50), test.Growth, truncateTestName(test.TestName, 80))
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Suite Wall Time per Run:\n")
+	for _, run := range report.Runs {
+		fmt.Printf("  %s  %-8s %9s  %4d tests  %s", formatTimestamp(run.Timestamp), run.Status,
+			formatSeconds(run.Seconds), run.Tests, run.URL)
+		if run.JobSeconds > 0 {
+			fmt.Printf(" (job %s", formatSeconds(run.JobSeconds))
+			if run.Cluster != "" {
+				fmt.Printf(" on %s", run.Cluster)
+			}
+			fmt.Printf(")")
+		}
+		fmt.Println()
+	}
+}
+
+// formatSeconds formats a duration in seconds, rounded for display
+func formatSeconds(seconds float64) string {
+	d := time.Duration(seconds * float64(time.Second))
+	if d >= time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// FormatPullRequestReport displays the attempts of a pull request on every lane and, for
+// each failed test, whether it also failed without the pull request
+func FormatPullRequestReport(report *PullRequestReport, displayURLs bool) {
+	title := fmt.Sprintf("Pull Request #%d", report.Number)
+	if report.Author != "" {
+		title += " by " + report.Author
+	}
+	fmt.Println(title)
+	fmt.Printf("%s\n", strings.Repeat("=", len(title)))
+	if report.HeadSHA != "" {
+		fmt.Printf("Head: %s\n", report.HeadSHA)
+	}
+	fmt.Println()
+
+	fmt.Printf("Lanes:\n")
+	for _, lane := range report.Lanes {
+		fmt.Printf("  %-8s %s (%d attempts)\n", lane.LatestStatus, lane.JobName, len(lane.Attempts))
+		for _, attempt := range lane.Attempts {
+			sha := attempt.SHA
+			if len(sha) > 8 {
+				sha = sha[:8]
+			}
+			fmt.Printf("    %s  %-8s %-8s %s\n", formatTimestamp(attempt.Timestamp), attempt.Status, sha, attempt.URL)
+			for _, test := range attempt.FailedTests {
+				fmt.Printf("      %s\n", truncateTestName(test, 100))
+			}
+		}
+	}
+	fmt.Println()
+	defer formatLaneErrors(report.LaneErrors)
+
+	if len(report.FailedTests) == 0 {
+		fmt.Printf("No test failures\n")
+		return
+	}
+
+	fmt.Printf("Failed Tests (compared with the last %s of the same lanes):\n", report.Compared)
+	for _, test := range report.FailedTests {
+		fmt.Printf("  %s\n", test.TestName)
+		fmt.Printf("    failed %d times on %s\n", test.Failures, strings.Join(test.Lanes, ", "))
+		if test.FailingElsewhere {
+			var elsewhere []string
+			if len(test.OtherPRs) > 0 {
+				elsewhere = append(elsewhere, fmt.Sprintf("%d other PRs", len(test.OtherPRs)))
+			}
+			if test.PeriodicFailures > 0 {
+				elsewhere = append(elsewhere, fmt.Sprintf("%d periodic runs", test.PeriodicFailures))
+			}
+			if len(elsewhere) == 0 {
+				elsewhere = append(elsewhere, fmt.Sprintf("%d other runs", len(test.ElsewhereURLs)))
+			}
+			fmt.Printf("    🟢 also failing on %s - likely not caused by this PR\n", strings.Join(elsewhere, " and "))
+			if displayURLs {
+				for _, url := range test.ElsewhereURLs {
+					fmt.Printf("      %s\n", url)
+				}
+			}
+		} else if len(test.UncomparedLanes) > 0 {
+			fmt.Printf("    🟡 not failing in the compared runs, but %s could not be fetched - unknown whether caused by this PR\n",
+				strings.Join(test.UncomparedLanes, ", "))
+		} else {
+			fmt.Printf("    🔴 not failing elsewhere - may be caused by this PR\n")
+		}
+	}
+}
+
+// FormatTestHistory displays the outcomes of the matching tests per lane, most failures
+// first, followed by the distribution of their failure signatures
+func FormatTestHistory(history *TestHistory, displayFailures bool) {
+	title := fmt.Sprintf("Test History: %s", history.Pattern)
+	fmt.Println(title)
+	fmt.Printf("%s\n\n", strings.Repeat("=", len(title)))
+	// The answer is incomplete without these lanes, whatever it is
+	defer formatLaneErrors(history.LaneErrors)
+
+	if len(history.Lanes) == 0 {
+		fmt.Printf("No matching test ran in the last %s\n", history.Window)
+		return
+	}
+
+	fmt.Printf("Matching Tests:   %d\n", len(history.Tests))
+	fmt.Printf("Executions:       %d (%d passed, %d failed) in the last %s\n\n",
+		history.Executions, history.Passes, history.Failures, history.Window)
+
+	fmt.Printf("Lanes:\n")
+	fmt.Printf("  %6s %6s %6s  %-23s %-23s  %s\n", "pass%", "passed", "failed", "first failure", "last failure", "lane")
+	for _, lane := range history.Lanes {
+		first, last := "-", "-"
+		if lane.FirstFailure != "" {
+			first = formatTimestamp(lane.FirstFailure)
+			last = formatTimestamp(lane.LastFailure)
+		}
+		fmt.Printf("  %5.1f%% %6d %6d  %-23s %-23s  %s\n", lane.PassRate, lane.Passes, lane.Failures, first, last, lane.JobName)
+	}
+	fmt.Println()
+
+	if len(history.Signatures) > 0 {
+		fmt.Printf("Failure Signatures:\n")
+		for _, cluster := range history.Signatures {
+			fmt.Printf("  %d\t%s\n", cluster.Count, truncateTestName(cluster.Signature, 120))
+			if len(cluster.Lanes) > 0 {
+				fmt.Printf("  \tin %s\n", strings.Join(cluster.Lanes, ", "))
+			}
+			if displayFailures && cluster.SampleMessage != "" {
+				fmt.Printf("  \t%s\n", cluster.SampleMessage)
+			}
+		}
+	}
+}
+
+// formatLaneErrors lists the lanes left out of an analysis because their runs could not be fetched
+func formatLaneErrors(laneErrors []LaneError) {
+	if len(laneErrors) == 0 {
+		return
+	}
+	fmt.Printf("\nLanes that could not be fetched (%d):\n", len(laneErrors))
+	for _, laneError := range laneErrors {
+		fmt.Printf("  %s: %s\n", laneError.JobName, laneError.Error)
+	}
+}
+
+// FormatLanes displays discovered lanes with their type, newest run and recent failure rate
+func FormatLanes(lanes []LaneInfo, window string) {
+	if len(lanes) == 0 {
+		fmt.Println("No lanes found")
+		return
+	}
+
+	fmt.Printf("%-10s %-23s %-8s %14s  %s\n", "TYPE", "LAST RUN", "STATUS", "FAILED/"+window, "LANE")
+	for _, lane := range lanes {
+		lastRun := "-"
+		if lane.LastRun != "" {
+			lastRun = formatTimestamp(lane.LastRun)
+		}
+		laneType := lane.Type
+		if laneType == "" {
+			laneType = "-"
+		}
+		status := lane.LastStatus
+		if status == "" {
+			status = "-"
+		}
+		failures := "-"
+		if lane.RecentRuns > 0 {
+			failures = fmt.Sprintf("%d/%d %5.1f%%", lane.FailedRuns, lane.RecentRuns, lane.FailureRate)
+		}
+		fmt.Printf("%-10s %-23s %-8s %14s  %s\n", laneType, lastRun, status, failures, lane.JobName)
+	}
+}
+
+// FormatLaneComparison displays the side-by-side health of several lanes and the tests
+// failing in only one of them
+func FormatLaneComparison(comparison *LaneComparison) {
+	title := fmt.Sprintf("Lane Comparison (%d lanes)", len(comparison.Lanes))
+	fmt.Println(title)
+	fmt.Printf("%s\n\n", strings.Repeat("=", len(title)))
+
+	fmt.Printf("  %5s %6s %6s %6s  %s\n", "runs", "failed", "fail%", "infra%", "lane")
+	for _, lane := range comparison.Lanes {
+		if lane.Error != "" {
+			fmt.Printf("  %5s %6s %6s %6s  %s (%s)\n", "-", "-", "-", "-", lane.JobName, lane.Error)
+			continue
+		}
+		fmt.Printf("  %5d %6d %5.1f%% %5.1f%%  %s\n", lane.TotalRuns, lane.FailedRuns,
+			lane.FailureRate, lane.InfrastructureFailureRate, lane.JobName)
+	}
+	fmt.Println()
+
+	fmt.Printf("Top Failures:\n")
+	for _, lane := range comparison.Lanes {
+		if lane.Error != "" {
+			continue
+		}
+		fmt.Printf("  %s:\n", lane.JobName)
+		if len(lane.TopFailures) == 0 {
+			fmt.Printf("    (no test failures)\n")
+		}
+		for _, pattern := range lane.TopFailures {
+			fmt.Printf("    %d\t%s\n", pattern.Count, truncateTestName(pattern.TestName, 100))
+		}
+	}
+	fmt.Println()
+
+	if len(comparison.UniqueFailures) == 0 {
+		fmt.Printf("No test failed in only one lane\n")
+		return
+	}
+	fmt.Printf("Failing In Only One Lane:\n")
+	for _, unique := range comparison.UniqueFailures {
+		fmt.Printf("  %d\t%s\n", unique.Failures, truncateTestName(unique.TestName, 100))
+		fmt.Printf("  \tin %s", unique.JobName)
+		if len(unique.PassedIn) > 0 {
+			fmt.Printf(", passed in %s", strings.Join(unique.PassedIn, ", "))
+		}
+		if len(unique.NotRunIn) > 0 {
+			fmt.Printf(", not run in %s", strings.Join(unique.NotRunIn, ", "))
+		}
+		fmt.Println()
+	}
+}
+
+// FormatQuarantineEntries displays quarantined tests with their SIG, test_id, quarantine
+// date and, for tests found in a source checkout, their location
+func FormatQuarantineEntries(title string, entries []QuarantineEntry) {
+	fmt.Printf("%s (%d):\n", title, len(entries))
+	if len(entries) == 0 {
+		fmt.Printf("  (none)\n")
+		return
+	}
+	fmt.Printf("  %-22s %-8s %-10s  %s\n", "SIG", "TEST ID", "SINCE", "TEST")
+	for _, entry := range entries {
+		fmt.Printf("  %-22s %-8s %-10s  %s\n", orDash(entry.SIG), orDash(entry.TestID),
+			orDash(entry.DateQuarantined), truncateTestName(entry.FullName, 100))
+		if entry.Location != "" {
+			fmt.Printf("  %-22s %-8s %-10s  at %s\n", "", "", "", entry.Location)
+		}
+	}
+}
+
+// FormatQuarantineDiff displays the tests whose quarantine in the source is not yet
+// reflected in the published report
+func FormatQuarantineDiff(diff *QuarantineDiff) {
+	FormatQuarantineEntries("Pending quarantines (in source, not in the report yet)", diff.Pending)
+	fmt.Println()
+	FormatQuarantineEntries("Lifted quarantines (in the report, no longer in source)", diff.Lifted)
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
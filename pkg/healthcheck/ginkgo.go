@@ -0,0 +1,268 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ginkgoReportGlob matches the JSON reports written by ginkgo --json-report below a run's artifacts directory
+const ginkgoReportGlob = "artifacts/**report*.json"
+
+// ginkgoSuiteReport is the part of a Ginkgo suite report the tool uses
+type ginkgoSuiteReport struct {
+	SuiteDescription string
+	SuiteLabels      []string
+	RunTime          time.Duration
+	SpecReports      []ginkgoSpecReport
+}
+
+// ginkgoSpecReport is the part of a Ginkgo spec report the tool uses
+type ginkgoSpecReport struct {
+	ContainerHierarchyTexts  []string
+	ContainerHierarchyLabels [][]string
+	LeafNodeType             string
+	LeafNodeLocation         ginkgoLocation
+	LeafNodeText             string
+	LeafNodeLabels           []string
+	State                    string
+	RunTime                  time.Duration
+	Failure                  *ginkgoFailure
+}
+
+type ginkgoLocation struct {
+	FileName       string
+	LineNumber     int
+	FullStackTrace string
+}
+
+type ginkgoFailure struct {
+	Message         string
+	Location        ginkgoLocation
+	ForwardedPanic  string
+	FailureNodeType string
+}
+
+// String formats a location as file:line
+func (l ginkgoLocation) String() string {
+	if l.FileName == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", l.FileName, l.LineNumber)
+}
+
+// fullText joins the container and leaf texts the way Ginkgo names a spec
+func (s *ginkgoSpecReport) fullText() string {
+	texts := append([]string{}, s.ContainerHierarchyTexts...)
+	if s.LeafNodeText != "" {
+		texts = append(texts, s.LeafNodeText)
+	}
+	return strings.Join(texts, " ")
+}
+
+// labels returns the labels of the spec and its containers, without duplicates
+func (s *ginkgoSpecReport) labels() []string {
+	var labels []string
+	seen := make(map[string]bool)
+	add := func(values []string) {
+		for _, label := range values {
+			if !seen[label] {
+				seen[label] = true
+				labels = append(labels, label)
+			}
+		}
+	}
+	for _, containerLabels := range s.ContainerHierarchyLabels {
+		add(containerLabels)
+	}
+	add(s.LeafNodeLabels)
+	return labels
+}
+
+// junitName returns the testcase name the Ginkgo junit reporter uses for the spec
+func (s *ginkgoSpecReport) junitName() string {
+	name := fmt.Sprintf("[%s]", s.LeafNodeType)
+	if text := s.fullText(); text != "" {
+		name += " " + text
+	}
+	if labels := s.labels(); len(labels) > 0 {
+		name += " [" + strings.Join(labels, ", ") + "]"
+	}
+	return name
+}
+
+// failed reports whether the spec ended in one of Ginkgo's failure states
+func (s *ginkgoSpecReport) failed() bool {
+	switch s.State {
+	case "failed", "panicked", "interrupted", "aborted", "timedout":
+		return true
+	}
+	return false
+}
+
+// parseGinkgoReport parses a Ginkgo JSON report, a list of suite reports
+func parseGinkgoReport(data []byte) ([]ginkgoSuiteReport, error) {
+	var reports []ginkgoSuiteReport
+	if err := json.Unmarshal(data, &reports); err != nil {
+		return nil, err
+	}
+	for _, report := range reports {
+		if report.SuiteDescription != "" || len(report.SpecReports) > 0 {
+			return reports, nil
+		}
+	}
+	return nil, fmt.Errorf("not a Ginkgo report")
+}
+
+// ginkgoTestcases converts the specs of Ginkgo suite reports into testcases, named like
+// the Ginkgo junit reporter names them. Skipped and pending specs become skipped testcases.
+func ginkgoTestcases(reports []ginkgoSuiteReport, sourceFile string) []Testcase {
+	var testcases []Testcase
+	for _, report := range reports {
+		for i := range report.SpecReports {
+			spec := &report.SpecReports[i]
+			testcase := Testcase{
+				Classname:  report.SuiteDescription,
+				Name:       spec.junitName(),
+				Time:       fmt.Sprintf("%.3f", spec.RunTime.Seconds()),
+				SourceFile: sourceFile,
+			}
+			applyGinkgoSpec(&testcase, spec, report.SuiteLabels)
+
+			if spec.State == "skipped" || spec.State == "pending" {
+				testcase.Skipped = &Skipped{Message: spec.State}
+			}
+
+			if spec.failed() && spec.Failure != nil {
+				value := spec.Failure.Message
+				if spec.Failure.ForwardedPanic != "" {
+					value += "\n" + spec.Failure.ForwardedPanic
+				}
+				if location := spec.Failure.Location.String(); location != "" {
+					value += "\n" + location
+				}
+				if spec.Failure.Location.FullStackTrace != "" {
+					value += "\n" + spec.Failure.Location.FullStackTrace
+				}
+				testcase.Failure = &Failure{Message: spec.Failure.Message, Type: spec.State, Value: value}
+			}
+			testcases = append(testcases, testcase)
+		}
+	}
+	return testcases
+}
+
+// applyGinkgoSpec copies the metadata only known from a Ginkgo report onto a testcase
+func applyGinkgoSpec(testcase *Testcase, spec *ginkgoSpecReport, suiteLabels []string) {
+	testcase.Labels = spec.labels()
+	for _, label := range suiteLabels {
+		if !testcase.HasLabel(label) {
+			testcase.Labels = append(testcase.Labels, label)
+		}
+	}
+	testcase.Location = spec.LeafNodeLocation.String()
+	if spec.failed() && spec.Failure != nil {
+		testcase.FailureNodeType = spec.Failure.FailureNodeType
+		testcase.FailureLocation = spec.Failure.Location.String()
+	}
+}
+
+// enrichWithGinkgo adds labels, locations and failure node types from Ginkgo reports to the
+// junit testcases describing the same specs. Specs are matched by their junit name or by
+// their full text, as junit reporters differ in whether they add the node type and labels.
+func enrichWithGinkgo(testsuite *Testsuite, reports []ginkgoSuiteReport) {
+	type match struct {
+		spec        *ginkgoSpecReport
+		suiteLabels []string
+	}
+	specs := make(map[string]match)
+	for r := range reports {
+		for i := range reports[r].SpecReports {
+			spec := &reports[r].SpecReports[i]
+			m := match{spec: spec, suiteLabels: reports[r].SuiteLabels}
+			specs[spec.junitName()] = m
+			if text := spec.fullText(); text != "" {
+				specs[text] = m
+				specs[fmt.Sprintf("[%s] %s", spec.LeafNodeType, text)] = m
+			}
+		}
+	}
+
+	for i := range testsuite.Testcase {
+		testcase := &testsuite.Testcase[i]
+		if m, ok := specs[testcase.Name]; ok {
+			applyGinkgoSpec(testcase, m.spec, m.suiteLabels)
+		}
+	}
+}
+
+// fetchGinkgoReports fetches and parses the Ginkgo JSON reports of a run. Reports are
+// optional, so unlistable artifacts and unreadable reports are skipped; only a
+// cancelled context is returned as an error.
+func fetchGinkgoReports(ctx context.Context, source Source, jobRun *JobRun) (map[string][]ginkgoSuiteReport, error) {
+	listed, err := source.ListArtifacts(ctx, jobRun, ginkgoReportGlob)
+	if err != nil {
+		return nil, ctx.Err()
+	}
+
+	var paths []string
+	for _, artifactPath := range listed {
+		if strings.Contains(path.Base(artifactPath), "report") {
+			paths = append(paths, artifactPath)
+		}
+	}
+	sort.Strings(paths)
+
+	reports := make(map[string][]ginkgoSuiteReport)
+	for _, reportPath := range paths {
+		body, err := source.FetchArtifact(ctx, jobRun, reportPath)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			continue
+		}
+		if body == nil {
+			continue
+		}
+		if parsed, err := parseGinkgoReport(body); err == nil {
+			reports[reportPath] = parsed
+		}
+	}
+	return reports, nil
+}
+
+// applyGinkgoReports enriches the junit testcases of a run with its Ginkgo reports. When
+// the run has no junit results the testcases are built from the reports instead.
+func applyGinkgoReports(testsuite *Testsuite, reports map[string][]ginkgoSuiteReport) *Testsuite {
+	if len(reports) == 0 {
+		return testsuite
+	}
+
+	paths := make([]string, 0, len(reports))
+	for reportPath := range reports {
+		paths = append(paths, reportPath)
+	}
+	sort.Strings(paths)
+
+	if testsuite != nil {
+		for _, reportPath := range paths {
+			enrichWithGinkgo(testsuite, reports[reportPath])
+		}
+		return testsuite
+	}
+
+	testsuite = &Testsuite{}
+	for _, reportPath := range paths {
+		testsuite.Testcase = append(testsuite.Testcase, ginkgoTestcases(reports[reportPath], reportPath)...)
+		for _, report := range reports[reportPath] {
+			testsuite.Time = addSuiteTimes(testsuite.Time, fmt.Sprintf("%.3f", report.RunTime.Seconds()))
+		}
+	}
+	setTestsuiteCounts(testsuite)
+	return testsuite
+}
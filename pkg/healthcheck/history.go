@@ -0,0 +1,741 @@
+package healthcheck
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // Registers the pure Go "sqlite" driver
+)
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	job_name  TEXT NOT NULL,
+	id        TEXT NOT NULL,
+	url       TEXT NOT NULL,
+	status    TEXT NOT NULL,
+	job_type  TEXT NOT NULL,
+	timestamp TEXT NOT NULL,
+	has_junit INTEGER NOT NULL,
+	PRIMARY KEY (job_name, id)
+);
+CREATE INDEX IF NOT EXISTS runs_url ON runs (url);
+
+CREATE TABLE IF NOT EXISTS testcases (
+	job_name        TEXT NOT NULL,
+	run_id          TEXT NOT NULL,
+	position        INTEGER NOT NULL,
+	classname       TEXT NOT NULL,
+	name            TEXT NOT NULL,
+	time            TEXT NOT NULL,
+	failed          INTEGER NOT NULL,
+	failure_message TEXT NOT NULL,
+	failure_type    TEXT NOT NULL,
+	failure_value   TEXT NOT NULL,
+	source_file     TEXT NOT NULL,
+	PRIMARY KEY (job_name, run_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS sync_state (
+	job_name  TEXT NOT NULL PRIMARY KEY,
+	resume_id TEXT NOT NULL
+);
+`
+
+// historyMigrations add columns introduced after a database was created
+var historyMigrations = []struct {
+	table, column, definition string
+}{
+	// Databases synced before junit files were merged only stored a single file per run
+	{"testcases", "source_file", "TEXT NOT NULL DEFAULT 'artifacts/junit.xml'"},
+	// Metadata read from Ginkgo reports; labels are comma separated, which Ginkgo forbids in labels
+	{"testcases", "labels", "TEXT NOT NULL DEFAULT ''"},
+	{"testcases", "location", "TEXT NOT NULL DEFAULT ''"},
+	{"testcases", "failure_node_type", "TEXT NOT NULL DEFAULT ''"},
+	{"testcases", "failure_location", "TEXT NOT NULL DEFAULT ''"},
+	// Skipped testcases were stored as passed before outcomes were tracked
+	{"testcases", "skipped", "INTEGER NOT NULL DEFAULT 0"},
+	{"testcases", "skipped_message", "TEXT NOT NULL DEFAULT ''"},
+	{"runs", "suite_time", "TEXT NOT NULL DEFAULT ''"},
+	{"runs", "commit_set", "TEXT NOT NULL DEFAULT ''"},
+	// The run's prowjob.json as JSON, empty when it was not available
+	{"runs", "prowjob", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// HistoryDB is a local SQLite database of finished job runs and their testcases.
+// It implements Source, so stored history can be analyzed with the same code as live Prow data.
+type HistoryDB struct {
+	db      *sql.DB
+	orgRepo string // <org>_<repo> directory holding the pull request runs of the synced repository
+}
+
+// DefaultHistoryDBPath returns $XDG_DATA_HOME/healthcheck/history.db, falling back to ~/.local/share
+func DefaultHistoryDBPath() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine history database location: %w", err)
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "healthcheck", "history.db"), nil
+}
+
+// OpenHistoryDB opens the history database at path, creating it if needed. Pull request
+// runs are looked up for the repository of config.
+func OpenHistoryDB(path string, config ProwConfig) (*HistoryDB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history database directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history database: %w", err)
+	}
+	// A single connection serializes writers and avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize history database: %w", err)
+	}
+
+	h := &HistoryDB{db: db, orgRepo: config.Org + "_" + config.Repo}
+	if err := h.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// migrate adds the columns missing from databases created by older versions
+func (h *HistoryDB) migrate() error {
+	for _, migration := range historyMigrations {
+		var count int
+		err := h.db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`,
+			migration.table, migration.column).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to inspect history database: %w", err)
+		}
+		if count > 0 {
+			continue
+		}
+		_, err = h.db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`,
+			migration.table, migration.column, migration.definition))
+		if err != nil {
+			return fmt.Errorf("failed to migrate history database: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the database
+func (h *HistoryDB) Close() error {
+	return h.db.Close()
+}
+
+// Lanes returns the names of all lanes with stored runs
+func (h *HistoryDB) Lanes(ctx context.Context) ([]string, error) {
+	rows, err := h.db.QueryContext(ctx, `SELECT DISTINCT job_name FROM runs ORDER BY job_name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lanes: %w", err)
+	}
+	defer rows.Close()
+
+	var lanes []string
+	for rows.Next() {
+		var lane string
+		if err := rows.Scan(&lane); err != nil {
+			return nil, fmt.Errorf("failed to read lane: %w", err)
+		}
+		lanes = append(lanes, lane)
+	}
+	return lanes, rows.Err()
+}
+
+// ResumeBuildID returns the build ID the next sync of a lane starts from: the oldest run
+// the previous sync had to skip, or its newest stored run. It returns "" for lanes that
+// were never synced.
+func (h *HistoryDB) ResumeBuildID(ctx context.Context, jobName string) (string, error) {
+	var id string
+	err := h.db.QueryRowContext(ctx, `SELECT resume_id FROM sync_state WHERE job_name = ?`, jobName).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to query sync state of %s: %w", jobName, err)
+	}
+
+	// Databases synced before the sync state was recorded resume from the highest build ID
+	err = h.db.QueryRowContext(ctx,
+		`SELECT id FROM runs WHERE job_name = ? ORDER BY length(id) DESC, id DESC LIMIT 1`, jobName).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query newest run of %s: %w", jobName, err)
+	}
+	return id, nil
+}
+
+// setResumeBuildID records the build ID the next sync of a lane starts from
+func (h *HistoryDB) setResumeBuildID(ctx context.Context, jobName, id string) error {
+	_, err := h.db.ExecContext(ctx,
+		`INSERT INTO sync_state (job_name, resume_id) VALUES (?, ?)
+		 ON CONFLICT (job_name) DO UPDATE SET resume_id = excluded.resume_id`, jobName, id)
+	if err != nil {
+		return fmt.Errorf("failed to save sync state of %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// storedRunIDs returns the IDs of the stored runs of a lane whose build ID is at least fromID
+func (h *HistoryDB) storedRunIDs(ctx context.Context, jobName, fromID string) (map[string]bool, error) {
+	rows, err := h.db.QueryContext(ctx,
+		`SELECT id FROM runs WHERE job_name = ? AND (length(id) > length(?) OR (length(id) = length(?) AND id >= ?))`,
+		jobName, fromID, fromID, fromID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stored runs of %s: %w", jobName, err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to read stored run: %w", err)
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// SaveRun stores a run together with all testcases of its junit results, replacing
+// any previously stored copy. A nil testsuite records that the run had no junit results.
+func (h *HistoryDB) SaveRun(ctx context.Context, jobName string, run *JobRun, testsuite *Testsuite) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	suiteTime := ""
+	if testsuite != nil {
+		suiteTime = testsuite.Time
+	}
+	prowJob := ""
+	if run.ProwJob != nil {
+		data, err := json.Marshal(run.ProwJob)
+		if err != nil {
+			return fmt.Errorf("failed to encode prowjob of run %s: %w", run.ID, err)
+		}
+		prowJob = string(data)
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO runs (job_name, id, url, status, job_type, timestamp, has_junit, suite_time, commit_set, prowjob)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (job_name, id) DO UPDATE SET
+			url = excluded.url, status = excluded.status, job_type = excluded.job_type,
+			timestamp = excluded.timestamp, has_junit = excluded.has_junit, suite_time = excluded.suite_time,
+			commit_set = excluded.commit_set, prowjob = excluded.prowjob`,
+		jobName, run.ID, run.URL, run.Status, run.JobType, run.Timestamp, testsuite != nil, suiteTime, run.CommitSet, prowJob)
+	if err != nil {
+		return fmt.Errorf("failed to store run %s: %w", run.ID, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM testcases WHERE job_name = ? AND run_id = ?`, jobName, run.ID); err != nil {
+		return fmt.Errorf("failed to replace testcases of run %s: %w", run.ID, err)
+	}
+
+	if testsuite != nil {
+		for i, testcase := range testsuite.Testcase {
+			failure := Failure{}
+			if testcase.Failure != nil {
+				failure = *testcase.Failure
+			}
+			skipped := Skipped{}
+			if testcase.Skipped != nil {
+				skipped = *testcase.Skipped
+			}
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO testcases (job_name, run_id, position, classname, name, time,
+					failed, failure_message, failure_type, failure_value, source_file,
+					labels, location, failure_node_type, failure_location, skipped, skipped_message)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				jobName, run.ID, i, testcase.Classname, testcase.Name, testcase.Time,
+				testcase.Failure != nil, failure.Message, failure.Type, failure.Value, testcase.SourceFile,
+				strings.Join(testcase.Labels, ","), testcase.Location, testcase.FailureNodeType, testcase.FailureLocation,
+				testcase.Skipped != nil, skipped.Message)
+			if err != nil {
+				return fmt.Errorf("failed to store testcase of run %s: %w", run.ID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit run %s: %w", run.ID, err)
+	}
+	return nil
+}
+
+// Results builds ci-health style results from the stored runs within timePeriod,
+// so that merge can answer from the database
+func (h *HistoryDB) Results(ctx context.Context, timePeriod time.Duration) (*Results, error) {
+	runs, err := h.queryRuns(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make(map[string]*Job)
+	for _, run := range runs {
+		if run.Timestamp != "" && !IsWithinTimePeriod(run.Timestamp, timePeriod) {
+			continue
+		}
+
+		job, exists := jobs[run.jobName]
+		if !exists {
+			job = &Job{JobName: run.jobName}
+			jobs[run.jobName] = job
+		}
+
+		switch run.Status {
+		case "FAILURE":
+			job.FailureCount++
+			job.FailureURLs = append(job.FailureURLs, run.URL)
+		case "SUCCESS":
+			job.SuccessCount++
+		}
+	}
+
+	results := &Results{}
+	for _, job := range jobs {
+		if job.FailureCount > 0 {
+			results.Data.SIGRetests.FailedJobLeaderBoard = append(results.Data.SIGRetests.FailedJobLeaderBoard, *job)
+		}
+	}
+
+	// Match the ci-health leaderboard, which lists the most failing lanes first
+	leaderboard := results.Data.SIGRetests.FailedJobLeaderBoard
+	sort.Slice(leaderboard, func(i, j int) bool {
+		if leaderboard[i].FailureCount != leaderboard[j].FailureCount {
+			return leaderboard[i].FailureCount > leaderboard[j].FailureCount
+		}
+		return leaderboard[i].JobName < leaderboard[j].JobName
+	})
+
+	return results, nil
+}
+
+// ListRuns returns the stored runs of a lane, newest first
+func (h *HistoryDB) ListRuns(ctx context.Context, jobName string, limit int, timePeriod time.Duration) ([]JobRun, error) {
+	stored, err := h.queryRuns(ctx, `WHERE job_name = ?`, jobName)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []JobRun
+	for _, run := range stored {
+		if len(runs) >= limit {
+			break
+		}
+		if timePeriod > 0 && run.Timestamp != "" && !IsWithinTimePeriod(run.Timestamp, timePeriod) {
+			continue
+		}
+		// Status and failures are filled in again by the analysis, as for live runs
+		runs = append(runs, JobRun{ID: run.ID, URL: run.URL, Timestamp: run.Timestamp})
+	}
+
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("%w for %s in the history database, run `healthcheck sync %s` first", ErrNoJobHistory, jobName, jobName)
+	}
+	return runs, nil
+}
+
+// ListPullRequestRuns returns the stored runs that tested a pull request, keyed by lane
+func (h *HistoryDB) ListPullRequestRuns(ctx context.Context, number int) (map[string][]JobRun, error) {
+	stored, err := h.queryRuns(ctx, `WHERE url LIKE ?`, fmt.Sprintf("%%/pr-logs/pull/%s/%d/%%", h.orgRepo, number))
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make(map[string][]JobRun)
+	for _, run := range stored {
+		runs[run.jobName] = append(runs[run.jobName], JobRun{ID: run.ID, URL: run.URL, Timestamp: run.Timestamp})
+	}
+	return runs, nil
+}
+
+// FetchProwJob returns the stored status and type of a run
+func (h *HistoryDB) FetchProwJob(ctx context.Context, run *JobRun) (*ProwJobInfo, error) {
+	stored, err := h.runByURL(ctx, run.URL)
+	if err != nil {
+		return nil, err
+	}
+	// Stored statuses are the normalized ones, map them back to prowjob states
+	info := &ProwJobInfo{Status: strings.ToLower(stored.Status), JobType: stored.JobType, CommitSet: stored.CommitSet}
+	if stored.prowJob != "" {
+		if prowJob, err := ParseProwJob([]byte(stored.prowJob)); err == nil {
+			info.ProwJob = prowJob
+		}
+	}
+	return info, nil
+}
+
+// FetchArtifact serves the stored testcases read from a junit file as a junit document.
+// Other artifacts are not stored.
+func (h *HistoryDB) FetchArtifact(ctx context.Context, run *JobRun, artifactPath string) ([]byte, error) {
+	name := path.Base(artifactPath)
+	if !strings.HasPrefix(name, "junit") || !strings.HasSuffix(name, ".xml") {
+		return nil, nil
+	}
+
+	stored, err := h.runByURL(ctx, run.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.hasJunit {
+		return nil, nil
+	}
+
+	testsuite, err := h.testsuite(ctx, stored.jobName, stored.ID, artifactPath)
+	if err != nil {
+		return nil, err
+	}
+	if testsuite == nil {
+		return nil, nil
+	}
+
+	data, err := xml.Marshal(testsuite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stored testcases of %s: %w", run.URL, err)
+	}
+	return data, nil
+}
+
+// ListArtifacts lists the junit files the stored testcases of a run were read from
+func (h *HistoryDB) ListArtifacts(ctx context.Context, run *JobRun, glob string) ([]string, error) {
+	stored, err := h.runByURL(ctx, run.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.hasJunit {
+		return nil, nil
+	}
+
+	files, err := h.sourceFiles(ctx, stored.jobName, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		// The run had junit results without any testcases
+		files = []string{"artifacts/junit.xml"}
+	}
+
+	pattern := globRegexp(glob)
+	var matching []string
+	for _, file := range files {
+		if pattern.MatchString(file) {
+			matching = append(matching, file)
+		}
+	}
+	return matching, nil
+}
+
+// FetchBuildLog always fails because build logs are not stored
+func (h *HistoryDB) FetchBuildLog(ctx context.Context, run *JobRun) ([]byte, error) {
+	return nil, fmt.Errorf("build logs are not stored in the history database")
+}
+
+// storedRun is a run row of the database
+type storedRun struct {
+	JobRun
+	jobName   string
+	hasJunit  bool
+	suiteTime string
+	prowJob   string
+}
+
+// queryRuns returns the runs matching the where clause, newest first
+func (h *HistoryDB) queryRuns(ctx context.Context, where string, args ...interface{}) ([]storedRun, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT job_name, id, url, status, job_type, timestamp, has_junit, suite_time, commit_set, prowjob
+		FROM runs `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []storedRun
+	for rows.Next() {
+		var run storedRun
+		if err := rows.Scan(&run.jobName, &run.ID, &run.URL, &run.Status, &run.JobType, &run.Timestamp, &run.hasJunit, &run.suiteTime, &run.CommitSet, &run.prowJob); err != nil {
+			return nil, fmt.Errorf("failed to read run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	// Build IDs grow over time, so they order runs whose timestamps are missing or equal
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Timestamp != runs[j].Timestamp {
+			return runs[i].Timestamp > runs[j].Timestamp
+		}
+		return compareBuildIDs(runs[i].ID, runs[j].ID) > 0
+	})
+
+	return runs, nil
+}
+
+// runByURL returns the stored run with the given URL
+func (h *HistoryDB) runByURL(ctx context.Context, url string) (*storedRun, error) {
+	runs, err := h.queryRuns(ctx, `WHERE url = ?`, url)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("run %s not found in the history database", url)
+	}
+	return &runs[0], nil
+}
+
+// sourceFiles returns the junit files the stored testcases of a run were read from
+func (h *HistoryDB) sourceFiles(ctx context.Context, jobName, runID string) ([]string, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT DISTINCT source_file FROM testcases WHERE job_name = ? AND run_id = ?
+		ORDER BY source_file`, jobName, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query junit files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var file string
+		if err := rows.Scan(&file); err != nil {
+			return nil, fmt.Errorf("failed to read junit file: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// testsuite rebuilds a junit file of a stored run. It returns nil if no testcases were
+// read from that file, unless the run's junit results had no testcases at all.
+func (h *HistoryDB) testsuite(ctx context.Context, jobName, runID, sourceFile string) (*Testsuite, error) {
+	files, err := h.sourceFiles(ctx, jobName, runID)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 && !containsString(files, sourceFile) {
+		return nil, nil
+	}
+
+	return h.queryTestsuite(ctx, jobName, `WHERE job_name = ? AND run_id = ? AND source_file = ?`, jobName, runID, sourceFile)
+}
+
+// fetchTestsuite returns all stored testcases of a run, including the Ginkgo metadata
+// a junit document cannot carry
+func (h *HistoryDB) fetchTestsuite(ctx context.Context, run *JobRun) (*Testsuite, error) {
+	stored, err := h.runByURL(ctx, run.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.hasJunit {
+		return nil, nil
+	}
+
+	testsuite, err := h.queryTestsuite(ctx, stored.jobName, `WHERE job_name = ? AND run_id = ?`, stored.jobName, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	testsuite.Time = stored.suiteTime
+	return testsuite, nil
+}
+
+// queryTestsuite returns the testcases matching the where clause as a suite named name, in stored order
+func (h *HistoryDB) queryTestsuite(ctx context.Context, name, where string, args ...interface{}) (*Testsuite, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT classname, name, time, failed, failure_message, failure_type, failure_value,
+			source_file, labels, location, failure_node_type, failure_location, skipped, skipped_message
+		FROM testcases `+where+` ORDER BY position`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query testcases: %w", err)
+	}
+	defer rows.Close()
+
+	testsuite := &Testsuite{Name: name}
+	failures := 0
+	for rows.Next() {
+		var testcase Testcase
+		var failed bool
+		var failure Failure
+		var labels string
+		var skipped bool
+		var skippedMessage string
+		if err := rows.Scan(&testcase.Classname, &testcase.Name, &testcase.Time,
+			&failed, &failure.Message, &failure.Type, &failure.Value, &testcase.SourceFile,
+			&labels, &testcase.Location, &testcase.FailureNodeType, &testcase.FailureLocation,
+			&skipped, &skippedMessage); err != nil {
+			return nil, fmt.Errorf("failed to read testcase: %w", err)
+		}
+		if failed {
+			testcase.Failure = &failure
+			failures++
+		}
+		if skipped {
+			testcase.Skipped = &Skipped{Message: skippedMessage}
+		}
+		if labels != "" {
+			testcase.Labels = strings.Split(labels, ",")
+		}
+		testsuite.Testcase = append(testsuite.Testcase, testcase)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read testcases: %w", err)
+	}
+
+	testsuite.Tests = strconv.Itoa(len(testsuite.Testcase))
+	testsuite.Failures = strconv.Itoa(failures)
+	return testsuite, nil
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// compareBuildIDs compares two numeric Prow build IDs
+func compareBuildIDs(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// runsSinceLister is implemented by sources that can list the runs of a job from a build ID on
+type runsSinceLister interface {
+	ListRunsSince(ctx context.Context, jobName string, limit int, buildID string) ([]JobRun, error)
+}
+
+// listRunsSince returns the runs of a job whose build ID is at least buildID, newest first
+func listRunsSince(ctx context.Context, source Source, jobName string, limit int, buildID string) ([]JobRun, error) {
+	if lister, ok := source.(runsSinceLister); ok {
+		return lister.ListRunsSince(ctx, jobName, limit, buildID)
+	}
+	runs, err := source.ListRuns(ctx, jobName, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+	var since []JobRun
+	for _, run := range runs {
+		if compareBuildIDs(run.ID, buildID) >= 0 {
+			since = append(since, run)
+		}
+	}
+	return since, nil
+}
+
+// SyncLane stores the finished runs of a lane that are not in the database yet. It resumes
+// from the build ID recorded by the previous sync, and looks back initialPeriod for lanes
+// that were never synced. A resumed sync stores the oldest maxRuns new runs, so runs left
+// over are stored by the next sync. It returns the number of stored runs.
+func SyncLane(ctx context.Context, source Source, db *HistoryDB, jobName string, initialPeriod time.Duration, maxRuns, parallel int) (int, error) {
+	resumeID, err := db.ResumeBuildID(ctx, jobName)
+	if err != nil {
+		return 0, err
+	}
+
+	var runs []JobRun
+	if resumeID == "" {
+		runs, err = source.ListRuns(ctx, jobName, maxRuns, initialPeriod)
+	} else {
+		// Page down to resumeID, the newest maxRuns runs may not reach it
+		runs, err = listRunsSince(ctx, source, jobName, math.MaxInt32, resumeID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch job history for %s: %w", jobName, err)
+	}
+
+	// Skip runs stored by an earlier sync
+	stored, err := db.storedRunIDs(ctx, jobName, resumeID)
+	if err != nil {
+		return 0, err
+	}
+	var newRuns []JobRun
+	for _, run := range runs {
+		if !stored[run.ID] {
+			newRuns = append(newRuns, run)
+		}
+	}
+
+	// The next sync resumes from the newest listed run, or from the newest run kept when
+	// there are more than maxRuns new runs, or from the oldest skipped one
+	nextID, skippedID := resumeID, ""
+	for _, run := range runs {
+		if compareBuildIDs(run.ID, nextID) > 0 {
+			nextID = run.ID
+		}
+	}
+	if len(newRuns) > maxRuns {
+		sort.Slice(newRuns, func(i, j int) bool {
+			return compareBuildIDs(newRuns[i].ID, newRuns[j].ID) < 0
+		})
+		newRuns = newRuns[:maxRuns]
+		nextID = newRuns[maxRuns-1].ID
+	}
+
+	testsuites := make([]*Testsuite, len(newRuns))
+	fetchErrors := make([]error, len(newRuns))
+	runParallel(len(newRuns), parallel, func(i int) {
+		fetchJobStatus(ctx, source, &newRuns[i])
+		testsuites[i], fetchErrors[i] = fetchJobTestsuite(ctx, source, &newRuns[i])
+	})
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for i := range newRuns {
+		// Pending runs are picked up by a later sync once they finish, as are runs
+		// whose junit files could not be fetched
+		if newRuns[i].Status == "PENDING" || fetchErrors[i] != nil {
+			if skippedID == "" || compareBuildIDs(newRuns[i].ID, skippedID) < 0 {
+				skippedID = newRuns[i].ID
+			}
+			continue
+		}
+		if err := db.SaveRun(ctx, jobName, &newRuns[i], testsuites[i]); err != nil {
+			return synced, err
+		}
+		synced++
+	}
+
+	if skippedID != "" {
+		nextID = skippedID
+	}
+	if nextID != "" && nextID != resumeID {
+		if err := db.setResumeBuildID(ctx, jobName, nextID); err != nil {
+			return synced, err
+		}
+	}
+	return synced, nil
+}
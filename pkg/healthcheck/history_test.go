@@ -0,0 +1,71 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSyncLaneResumesBelowLimit(t *testing.T) {
+	const lane = "pull-kubevirt-unit-test"
+	root := t.TempDir()
+	addBuild := func(id int) {
+		dir := filepath.Join(root, lane, fmt.Sprint(id))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		started := time.Date(2025, 8, 14, 0, id, 0, 0, time.UTC).Format(time.RFC3339)
+		prowJob := fmt.Sprintf(`{"spec": {"type": "presubmit", "job": %q}, "status": {"state": "success", "startTime": %q, "build_id": "%d"}}`, lane, started, id)
+		if err := os.WriteFile(filepath.Join(dir, "prowjob.json"), []byte(prowJob), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db, err := OpenHistoryDB(filepath.Join(t.TempDir(), "history.db"), DefaultProwConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ctx := context.Background()
+	source := NewLocalSource(root)
+
+	addBuild(8)
+	addBuild(9)
+	if _, err := SyncLane(ctx, source, db, lane, 0, 2, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// More new runs than the limit, the oldest are stored first
+	for id := 10; id <= 14; id++ {
+		addBuild(id)
+	}
+	for _, want := range [][]string{{"10", "11"}, {"12", "13"}, {"14"}, {}} {
+		before, err := db.storedRunIDs(ctx, lane, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		synced, err := SyncLane(ctx, source, db, lane, 0, 2, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := db.storedRunIDs(ctx, lane, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for id := range after {
+			if !before[id] {
+				got = append(got, id)
+			}
+		}
+		sort.Strings(got)
+		if synced != len(want) || !reflect.DeepEqual(got, want) {
+			t.Errorf("SyncLane() stored %d runs %v, want %v", synced, got, want)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// junitGlob matches the junit files anywhere below a run's artifacts directory
+const junitGlob = "artifacts/**junit*.xml"
+
+// defaultJunitPaths are probed when the artifacts of a run cannot be listed
+var defaultJunitPaths = []string{
+	"artifacts/junit/junit.unittests.xml", // Unit tests
+	"artifacts/junit.functest.xml",        // Functional tests
+	"artifacts/junit.xml",                 // Generic
+	"artifacts/tests/junit.xml",           // Alternative location
+}
+
+// parseJunit parses a junit document with either a <testsuites> or a <testsuite> root,
+// flattening nested suites into a single suite
+func parseJunit(data []byte) (*Testsuite, error) {
+	root, err := junitRoot(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var suites []Testsuite
+	var rootTime string
+	switch root {
+	case "testsuites":
+		var testsuites Testsuites
+		if err := xml.Unmarshal(data, &testsuites); err != nil {
+			return nil, err
+		}
+		suites = testsuites.Testsuites
+		rootTime = testsuites.Time
+	case "testsuite":
+		var testsuite Testsuite
+		if err := xml.Unmarshal(data, &testsuite); err != nil {
+			return nil, err
+		}
+		suites = []Testsuite{testsuite}
+	default:
+		return nil, fmt.Errorf("unexpected root element <%s>", root)
+	}
+
+	merged := &Testsuite{Time: rootTime}
+	if len(suites) == 1 {
+		merged.Name = suites[0].Name
+	}
+	if merged.Time == "" {
+		for i := range suites {
+			merged.Time = addSuiteTimes(merged.Time, suites[i].Time)
+		}
+	}
+	for i := range suites {
+		merged.Testcase = append(merged.Testcase, flattenTestcases(&suites[i])...)
+	}
+	setTestsuiteCounts(merged)
+
+	return merged, nil
+}
+
+// junitRoot returns the name of the root element of an XML document
+func junitRoot(data []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("empty junit document")
+		}
+		if err != nil {
+			return "", err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local, nil
+		}
+	}
+}
+
+// flattenTestcases returns the testcases of a suite and all of its nested suites
+func flattenTestcases(suite *Testsuite) []Testcase {
+	testcases := suite.Testcase
+	for i := range suite.Testsuites {
+		testcases = append(testcases, flattenTestcases(&suite.Testsuites[i])...)
+	}
+	return testcases
+}
+
+// addSuiteTimes adds two suite time attributes in seconds, ignoring missing or malformed ones
+func addSuiteTimes(a, b string) string {
+	total, known := 0.0, false
+	for _, value := range []string{a, b} {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			total += seconds
+			known = true
+		}
+	}
+	if !known {
+		return ""
+	}
+	return strconv.FormatFloat(total, 'f', 3, 64)
+}
+
+// setTestsuiteCounts recomputes the tests and failures attributes of a flattened suite
+func setTestsuiteCounts(suite *Testsuite) {
+	failures := 0
+	for _, testcase := range suite.Testcase {
+		if testcase.Failure != nil {
+			failures++
+		}
+	}
+	suite.Tests = strconv.Itoa(len(suite.Testcase))
+	suite.Failures = strconv.Itoa(failures)
+}
+
+// testsuiteSource is implemented by sources that store parsed testcases, such as the
+// history database, so they are returned with everything read from the original artifacts
+type testsuiteSource interface {
+	fetchTestsuite(ctx context.Context, jobRun *JobRun) (*Testsuite, error)
+}
+
+// fetchJobTestsuite fetches every junit file of a run and merges them into a single suite,
+// recording the file each testcase came from, then enriches it with the run's Ginkgo reports.
+// It returns nil when the run has neither junit files nor Ginkgo reports.
+func fetchJobTestsuite(ctx context.Context, source Source, jobRun *JobRun) (*Testsuite, error) {
+	if stored, ok := source.(testsuiteSource); ok {
+		return stored.fetchTestsuite(ctx, jobRun)
+	}
+
+	paths, err := junitArtifactPaths(ctx, source, jobRun)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged *Testsuite
+	var parseErrors []string
+	for _, junitPath := range paths {
+		body, err := source.FetchArtifact(ctx, jobRun, junitPath)
+		if err != nil {
+			return nil, err
+		}
+		if body == nil {
+			continue
+		}
+
+		testsuite, err := parseJunit(body)
+		if err != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("%s: %v", junitPath, err))
+			continue
+		}
+
+		if merged == nil {
+			merged = &Testsuite{Name: testsuite.Name, Time: testsuite.Time}
+		} else {
+			// Suite-level attributes only describe a single file
+			merged.Name, merged.Time = "", ""
+		}
+		for _, testcase := range testsuite.Testcase {
+			testcase.SourceFile = junitPath
+			merged.Testcase = append(merged.Testcase, testcase)
+		}
+	}
+
+	ginkgoReports, err := fetchGinkgoReports(ctx, source, jobRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if merged == nil && len(parseErrors) > 0 && len(ginkgoReports) == 0 {
+		return nil, fmt.Errorf("failed to parse junit files of %s as <testsuites> or <testsuite>: %s",
+			jobRun.URL, strings.Join(parseErrors, "; "))
+	}
+	if merged != nil {
+		setTestsuiteCounts(merged)
+	}
+
+	return applyGinkgoReports(merged, ginkgoReports), nil
+}
+
+// junitArtifactPaths lists the junit files of a run, falling back to the well-known
+// locations when the source cannot list the run's artifacts
+func junitArtifactPaths(ctx context.Context, source Source, jobRun *JobRun) ([]string, error) {
+	listed, err := source.ListArtifacts(ctx, jobRun, junitGlob)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return defaultJunitPaths, nil
+	}
+
+	var paths []string
+	for _, artifactPath := range listed {
+		if strings.HasPrefix(path.Base(artifactPath), "junit") {
+			paths = append(paths, artifactPath)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// globRegexp converts an artifact glob into an anchored regular expression
+func globRegexp(glob string) *regexp.Regexp {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			pattern.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			pattern.WriteString("[^/]*")
+		case glob[i] == '?':
+			pattern.WriteString("[^/]")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(string(glob[i])))
+		}
+	}
+	pattern.WriteString("$")
+	return regexp.MustCompile(pattern.String())
+}
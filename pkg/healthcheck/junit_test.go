@@ -0,0 +1,144 @@
+package healthcheck
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testcaseNames returns the names of a suite's testcases in order
+func testcaseNames(suite *Testsuite) []string {
+	var names []string
+	for _, testcase := range suite.Testcase {
+		names = append(names, testcase.Name)
+	}
+	return names
+}
+
+func TestParseJunit(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantName     string
+		wantTime     string
+		wantTests    string
+		wantFailures string
+		wantNames    []string
+	}{
+		{
+			name: "testsuite root",
+			data: `<testsuite name="unit" tests="2" failures="1" time="1.5">
+				<testcase name="a"/>
+				<testcase name="b"><failure message="boom"/></testcase>
+			</testsuite>`,
+			wantName: "unit", wantTime: "1.500", wantTests: "2", wantFailures: "1",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "testsuites root",
+			data: `<testsuites time="10">
+				<testsuite name="first" time="4"><testcase name="a"/></testsuite>
+				<testsuite name="second" time="6"><testcase name="b"><failure/></testcase></testsuite>
+			</testsuites>`,
+			wantTime: "10", wantTests: "2", wantFailures: "1",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "suite times added without a root time",
+			data: `<testsuites>
+				<testsuite name="first" time="4"><testcase name="a"/></testsuite>
+				<testsuite name="second" time="0.5"><testcase name="b"/></testsuite>
+			</testsuites>`,
+			wantTime: "4.500", wantTests: "2", wantFailures: "0",
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "nested suites",
+			data: `<testsuites>
+				<testsuite name="outer">
+					<testcase name="a"/>
+					<testsuite name="inner">
+						<testcase name="b"><failure/></testcase>
+						<testsuite name="innermost"><testcase name="c"><failure/></testcase></testsuite>
+					</testsuite>
+				</testsuite>
+			</testsuites>`,
+			wantName: "outer", wantTests: "3", wantFailures: "2",
+			wantNames: []string{"a", "b", "c"},
+		},
+	}
+	for _, tt := range tests {
+		suite, err := parseJunit([]byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: parseJunit() error: %v", tt.name, err)
+			continue
+		}
+		if suite.Name != tt.wantName || suite.Time != tt.wantTime || suite.Tests != tt.wantTests || suite.Failures != tt.wantFailures {
+			t.Errorf("%s: parseJunit() = name %q, time %q, tests %s, failures %s, want %q, %q, %s, %s", tt.name,
+				suite.Name, suite.Time, suite.Tests, suite.Failures, tt.wantName, tt.wantTime, tt.wantTests, tt.wantFailures)
+		}
+		if got := testcaseNames(suite); !reflect.DeepEqual(got, tt.wantNames) {
+			t.Errorf("%s: parseJunit() testcases = %v, want %v", tt.name, got, tt.wantNames)
+		}
+	}
+
+	for _, data := range []string{"", `<results><testcase name="a"/></results>`} {
+		if _, err := parseJunit([]byte(data)); err == nil {
+			t.Errorf("parseJunit(%q) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestFetchJobTestsuiteMergesJunitFiles(t *testing.T) {
+	build := filepath.Join(t.TempDir(), "pull-kubevirt-e2e", "100")
+	files := map[string]string{
+		"prowjob.json": `{"spec": {"job": "pull-kubevirt-e2e"}, "status": {"state": "failure", "build_id": "100"}}`,
+		"artifacts/junit.functest.xml": `<testsuite name="functest" time="30">
+			<testcase name="a"/>
+			<testcase name="b"><failure message="boom"/></testcase>
+		</testsuite>`,
+		"artifacts/junit/junit.unittests.xml": `<testsuites><testsuite name="unit"><testcase name="c"/></testsuite></testsuites>`,
+		"artifacts/junit.broken.xml":          `<testsuite`,
+		"artifacts/other.xml":                 `<testsuite name="other"><testcase name="d"/></testsuite>`,
+	}
+	for name, data := range files {
+		path := filepath.Join(build, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := NewLocalSource(filepath.Dir(filepath.Dir(build)))
+	runs, err := source.ListRuns(context.Background(), "pull-kubevirt-e2e", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite, err := fetchJobTestsuite(context.Background(), source, &runs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Suite-level attributes only describe a single file
+	if suite.Name != "" || suite.Time != "" || suite.Tests != "3" || suite.Failures != "1" {
+		t.Errorf("fetchJobTestsuite() = name %q, time %q, tests %s, failures %s, want \"\", \"\", 3, 1",
+			suite.Name, suite.Time, suite.Tests, suite.Failures)
+	}
+	wantSources := map[string]string{
+		"a": "artifacts/junit.functest.xml",
+		"b": "artifacts/junit.functest.xml",
+		"c": "artifacts/junit/junit.unittests.xml",
+	}
+	if len(suite.Testcase) != len(wantSources) {
+		t.Fatalf("fetchJobTestsuite() testcases = %v, want a, b and c", testcaseNames(suite))
+	}
+	for _, testcase := range suite.Testcase {
+		if testcase.SourceFile != wantSources[testcase.Name] {
+			t.Errorf("testcase %s SourceFile = %q, want %q", testcase.Name, testcase.SourceFile, wantSources[testcase.Name])
+		}
+	}
+}
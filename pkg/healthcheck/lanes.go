@@ -0,0 +1,203 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// laneLister is implemented by sources that know their lanes without ci-health
+type laneLister interface {
+	Lanes(ctx context.Context) ([]string, error)
+}
+
+// activeLaneLister is implemented by sources that can list the lanes of their repository
+// that started a run since a time
+type activeLaneLister interface {
+	ActiveLanes(ctx context.Context, since time.Time, match func(string) bool) ([]string, error)
+}
+
+// laneDiscoverer is implemented by sources that can list the lanes in their artifact
+// bucket, keyed by the directory each lane was found in
+type laneDiscoverer interface {
+	DiscoverLanes(ctx context.Context) (map[string]string, error)
+}
+
+// LaneDiscoveryConfig controls which lanes are discovered and how their health is measured
+type LaneDiscoveryConfig struct {
+	HealthURL  string         // ci-health results.json listing lanes with failures
+	JobRegex   *regexp.Regexp // Only lanes matching this regex are reported
+	TimePeriod time.Duration  // Window the failure rate is computed over
+	RunLimit   int            // Maximum number of recent runs fetched per lane
+	Parallel   int            // Number of lanes inspected concurrently
+}
+
+// LaneInfo describes a discovered lane and its recent health
+type LaneInfo struct {
+	JobName     string   `json:"job_name"`
+	Type        string   `json:"type,omitempty"` // presubmit, batch, postsubmit or periodic
+	FoundIn     []string `json:"found_in"`       // ci-health, pr-logs/directory, logs or history
+	LastRun     string   `json:"last_run,omitempty"`
+	LastStatus  string   `json:"last_status,omitempty"`
+	RecentRuns  int      `json:"recent_runs"`  // Finished runs within the window
+	FailedRuns  int      `json:"failed_runs"`  // Failed runs within the window
+	FailureRate float64  `json:"failure_rate"` // Percentage of finished runs that failed
+}
+
+// DiscoverLanes finds the lanes known to ci-health and to the source, either from its
+// bucket listings or from the history database, and measures the recent health of the
+// ones matching config.JobRegex. Lanes whose runs cannot be listed are still reported.
+func DiscoverLanes(ctx context.Context, source Source, config LaneDiscoveryConfig) ([]LaneInfo, error) {
+	foundIn := make(map[string][]string)
+	add := func(name, where string) {
+		if config.JobRegex.MatchString(name) && !containsString(foundIn[name], where) {
+			foundIn[name] = append(foundIn[name], where)
+		}
+	}
+
+	if config.HealthURL != "" {
+		results, err := FetchResults(ctx, config.HealthURL)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+		} else {
+			for _, job := range results.Data.SIGRetests.FailedJobLeaderBoard {
+				add(job.JobName, "ci-health")
+			}
+		}
+	}
+
+	if discoverer, ok := source.(laneDiscoverer); ok {
+		lanes, err := discoverer.DiscoverLanes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lanes: %w", err)
+		}
+		for name, dir := range lanes {
+			add(name, dir)
+		}
+	}
+	if lister, ok := source.(laneLister); ok {
+		lanes, err := lister.Lanes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range lanes {
+			add(name, "history")
+		}
+	}
+
+	infos := make([]LaneInfo, 0, len(foundIn))
+	for name, where := range foundIn {
+		infos = append(infos, LaneInfo{JobName: name, FoundIn: where})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].JobName < infos[j].JobName })
+
+	runParallel(len(infos), config.Parallel, func(i int) {
+		inspectLane(ctx, source, &infos[i], config)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
+
+// inspectLane fills in the type, last run and recent failure rate of a lane
+func inspectLane(ctx context.Context, source Source, info *LaneInfo, config LaneDiscoveryConfig) {
+	// The type follows from where the lane was found until its newest run tells otherwise
+	switch {
+	case containsString(info.FoundIn, "pr-logs/directory"):
+		info.Type = "presubmit"
+	case strings.HasPrefix(info.JobName, "periodic-"):
+		info.Type = "periodic"
+	case strings.HasPrefix(info.JobName, "post-"):
+		info.Type = "postsubmit"
+	}
+
+	runs, err := source.ListRuns(ctx, info.JobName, config.RunLimit, 0)
+	if err != nil || len(runs) == 0 {
+		return
+	}
+	sortRunsNewestFirst(runs)
+
+	for i := range runs {
+		if runs[i].Status == "" {
+			fetchJobStatus(ctx, source, &runs[i])
+		}
+	}
+	if prowJob, err := source.FetchProwJob(ctx, &runs[0]); err == nil && prowJob.JobType != "" {
+		info.Type = prowJob.JobType
+	}
+
+	info.LastRun = runs[0].Timestamp
+	info.LastStatus = runs[0].Status
+	for _, run := range runs {
+		if config.TimePeriod > 0 && run.Timestamp != "" && !IsWithinTimePeriod(run.Timestamp, config.TimePeriod) {
+			continue
+		}
+		switch run.Status {
+		case "SUCCESS":
+			info.RecentRuns++
+		case "FAILURE", "ERROR":
+			info.RecentRuns++
+			info.FailedRuns++
+		}
+	}
+	if info.RecentRuns > 0 {
+		info.FailureRate = float64(info.FailedRuns) / float64(info.RecentRuns) * 100
+	}
+}
+
+// KnownLanes returns the sorted names of the lanes matching jobRegex. Sources that store
+// their lanes, such as the history database, list them; otherwise the lanes are taken
+// from the ci-health results at healthURL.
+func KnownLanes(ctx context.Context, source Source, healthURL string, jobRegex *regexp.Regexp) ([]string, error) {
+	var names []string
+	if lister, ok := source.(laneLister); ok {
+		lanes, err := lister.Lanes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = lanes
+	} else {
+		results, err := FetchResults(ctx, healthURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch ci-health results: %w", err)
+		}
+		for _, job := range results.Data.SIGRetests.FailedJobLeaderBoard {
+			names = append(names, job.JobName)
+		}
+	}
+
+	var lanes []string
+	for _, name := range names {
+		if jobRegex.MatchString(name) && !containsString(lanes, name) {
+			lanes = append(lanes, name)
+		}
+	}
+	sort.Strings(lanes)
+	return lanes, nil
+}
+
+// ActiveLanes returns the sorted names of the lanes matching jobRegex that ran within
+// timePeriod, including lanes that only passed. Prow sources list the lanes of their
+// repository with a run in the window from their bucket; other sources fall back to
+// KnownLanes.
+func ActiveLanes(ctx context.Context, source Source, healthURL string, jobRegex *regexp.Regexp, timePeriod time.Duration) ([]string, error) {
+	lister, ok := source.(activeLaneLister)
+	if !ok {
+		return KnownLanes(ctx, source, healthURL, jobRegex)
+	}
+	var since time.Time
+	if timePeriod > 0 {
+		since = now().Add(-timePeriod)
+	}
+	lanes, err := lister.ActiveLanes(ctx, since, jobRegex.MatchString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lanes: %w", err)
+	}
+	return lanes, nil
+}
@@ -0,0 +1,86 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestActiveLanes(t *testing.T) {
+	withNow(t, time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC))
+
+	listings := map[string]string{
+		"pr-logs/directory/": `{"items": [
+			{"name": "pr-logs/directory/pull-kubevirt-e2e/latest-build.txt", "updated": "2025-08-14T10:00:00Z"},
+			{"name": "pr-logs/directory/pull-kubevirt-e2e-k8s-1.22/latest-build.txt", "updated": "2024-01-01T10:00:00Z"},
+			{"name": "pr-logs/directory/pull-kubevirtci-e2e/latest-build.txt", "updated": "2025-08-13T10:00:00Z"},
+			{"name": "pr-logs/directory/pull-kubevirt-unit/latest-build.txt", "updated": "2025-08-13T10:00:00Z"}
+		]}`,
+		"logs/": `{"items": [
+			{"name": "logs/periodic-kubevirt-e2e/latest-build.txt", "updated": "2025-08-12T10:00:00Z"},
+			{"name": "logs/periodic-other-e2e/latest-build.txt", "updated": "2025-08-12T10:00:00Z"}
+		]}`,
+	}
+	objects := map[string]string{
+		"/b/pr-logs/directory/pull-kubevirt-e2e/latest-build.txt":                    "101",
+		"/b/pr-logs/directory/pull-kubevirt-e2e/101.txt":                             "gs://b/pr-logs/pull/kubevirt_kubevirt/1/pull-kubevirt-e2e/101",
+		"/b/pr-logs/directory/pull-kubevirtci-e2e/latest-build.txt":                  "201",
+		"/b/pr-logs/directory/pull-kubevirtci-e2e/201.txt":                           "gs://b/pr-logs/pull/kubevirt_kubevirtci/5/pull-kubevirtci-e2e/201",
+		"/b/pr-logs/pull/kubevirt_kubevirtci/5/pull-kubevirtci-e2e/201/prowjob.json": `{"spec": {"refs": {"org": "kubevirt", "repo": "kubevirtci"}}, "status": {"state": "success"}}`,
+		"/b/logs/periodic-kubevirt-e2e/latest-build.txt":                             "301",
+		"/b/logs/periodic-kubevirt-e2e/301/prowjob.json":                             `{"spec": {"extra_refs": [{"org": "kubevirt", "repo": "kubevirt"}]}, "status": {"state": "success"}}`,
+		"/b/logs/periodic-other-e2e/latest-build.txt":                                "401",
+		"/b/logs/periodic-other-e2e/401/prowjob.json":                                `{"spec": {"extra_refs": [{"org": "other", "repo": "other"}]}, "status": {"state": "success"}}`,
+	}
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+		if r.URL.Path == "/storage/v1/b/b/o" {
+			prefix := r.URL.Query().Get("prefix")
+			if r.URL.Query().Get("matchGlob") != prefix+"*/latest-build.txt" {
+				t.Errorf("listing of %s matches %q", prefix, r.URL.Query().Get("matchGlob"))
+			}
+			io.WriteString(w, listings[prefix])
+			return
+		}
+		body, ok := objects[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+	withTransport(t, http.DefaultTransport)
+
+	config := DefaultProwConfig()
+	config.ProwURL, config.StorageURL, config.Bucket = server.URL, server.URL, "b"
+	source := NewProwSource(config, nil)
+
+	lanes, err := ActiveLanes(context.Background(), source, "", regexp.MustCompile("e2e"), 7*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"periodic-kubevirt-e2e", "pull-kubevirt-e2e"}; !reflect.DeepEqual(lanes, want) {
+		t.Errorf("ActiveLanes() = %v, want %v", lanes, want)
+	}
+	for _, path := range requested {
+		for _, lane := range []string{"pull-kubevirt-e2e-k8s-1.22", "pull-kubevirt-unit"} {
+			if path == fmt.Sprintf("/b/pr-logs/directory/%s/latest-build.txt", lane) {
+				t.Errorf("the runs of %s were read although it is stale or does not match", lane)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocalSource is a Source reading build folders from a local directory tree, for
+// example artifacts downloaded with gsutil or copied from a private cluster.
+// A build folder is any directory holding prowjob.json, finished.json or build-log.txt;
+// run URLs are file:// URLs of those folders. Build folders without an artifacts
+// directory are treated as downloads that flattened it into the build folder.
+type LocalSource struct {
+	root string
+}
+
+// NewLocalSource creates a Source for the build folders below root
+func NewLocalSource(root string) *LocalSource {
+	return &LocalSource{root: root}
+}
+
+// localBuildMarkers are the files identifying a directory as a build folder
+var localBuildMarkers = []string{"prowjob.json", "finished.json", "build-log.txt"}
+
+// ListRuns returns the build folders below the root directory, newest first. When jobName
+// is not empty only builds of that job are returned, identified by prowjob.json or the
+// name of the folder containing the build.
+func (s *LocalSource) ListRuns(ctx context.Context, jobName string, limit int, timePeriod time.Duration) ([]JobRun, error) {
+	var runs []JobRun
+
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.IsDir() || !isLocalBuildFolder(path) {
+			return nil
+		}
+
+		run, buildJob := s.localRun(path)
+		if jobName == "" || buildJob == jobName {
+			runs = append(runs, run)
+		}
+		// Build folders never nest
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", s.root, err)
+	}
+
+	runs = FilterRunsByTimePeriod(runs, timePeriod)
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("%w: no build folders found in %s", ErrNoJobHistory, s.root)
+	}
+
+	if limit <= 0 {
+		limit = len(runs)
+	}
+	return deduplicateAndLimitRuns(runs, limit), nil
+}
+
+// isLocalBuildFolder reports whether dir contains one of the build marker files
+func isLocalBuildFolder(dir string) bool {
+	for _, marker := range localBuildMarkers {
+		if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// localRun builds the JobRun of a build folder and returns the name of its job
+func (s *LocalSource) localRun(dir string) (JobRun, string) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		absDir = dir
+	}
+
+	run := JobRun{
+		ID:  filepath.Base(absDir),
+		URL: "file://" + filepath.ToSlash(absDir),
+	}
+	// Downloaded folders keep the bucket layout <job-name>/<build-id>
+	jobName := filepath.Base(filepath.Dir(absDir))
+
+	if data, err := os.ReadFile(filepath.Join(absDir, "prowjob.json")); err == nil {
+		if prowJob, err := ParseProwJob(data); err == nil {
+			if prowJob.Spec.Job != "" {
+				jobName = prowJob.Spec.Job
+			}
+			if prowJob.Status.BuildID != "" {
+				run.ID = prowJob.Status.BuildID
+			}
+			run.JobType = prowJob.JobType()
+			if started := prowJob.Started(); !started.IsZero() {
+				run.Timestamp = started.UTC().Format(time.RFC3339)
+			}
+			run.ProwJob = prowJob
+		}
+	}
+
+	if run.Timestamp == "" {
+		var started struct {
+			Timestamp int64 `json:"timestamp"`
+		}
+		if data, err := os.ReadFile(filepath.Join(absDir, "started.json")); err == nil &&
+			json.Unmarshal(data, &started) == nil && started.Timestamp > 0 {
+			run.Timestamp = time.Unix(started.Timestamp, 0).UTC().Format(time.RFC3339)
+		} else if info, err := os.Stat(absDir); err == nil {
+			run.Timestamp = info.ModTime().UTC().Format(time.RFC3339)
+		}
+	}
+
+	return run, jobName
+}
+
+// FetchProwJob reads the job status and type from prowjob.json, falling back to the
+// result recorded in finished.json
+func (s *LocalSource) FetchProwJob(ctx context.Context, run *JobRun) (*ProwJobInfo, error) {
+	dir := localRunDir(run.URL)
+
+	if data, err := os.ReadFile(filepath.Join(dir, "prowjob.json")); err == nil {
+		return parseProwJobInfo(data)
+	}
+
+	var finished struct {
+		Result string `json:"result"`
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "finished.json"))
+	if err != nil {
+		return nil, fmt.Errorf("prowjob.json not found")
+	}
+	if err := json.Unmarshal(data, &finished); err != nil || finished.Result == "" {
+		return nil, fmt.Errorf("could not find result in finished.json")
+	}
+	return &ProwJobInfo{Status: strings.ToLower(finished.Result), JobType: run.JobType}, nil
+}
+
+// FetchArtifact reads a file relative to the build folder
+func (s *LocalSource) FetchArtifact(ctx context.Context, run *JobRun, path string) ([]byte, error) {
+	data, err := os.ReadFile(localArtifactPath(localRunDir(run.URL), path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// ListArtifacts walks the build folder for files matching glob
+func (s *LocalSource) ListArtifacts(ctx context.Context, run *JobRun, glob string) ([]string, error) {
+	dir := localRunDir(run.URL)
+	flattened := !localHasArtifactsDir(dir)
+	pattern := globRegexp(glob)
+
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if flattened {
+			rel = "artifacts/" + rel
+		}
+		if pattern.MatchString(rel) {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts of %s: %w", dir, err)
+	}
+	return paths, nil
+}
+
+// FetchBuildLog reads the build-log.txt of a build folder
+func (s *LocalSource) FetchBuildLog(ctx context.Context, run *JobRun) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(localRunDir(run.URL), "build-log.txt"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("build log not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build log: %w", err)
+	}
+	return data, nil
+}
+
+// localArtifactPath maps an artifact path to a file of the build folder dir
+func localArtifactPath(dir, artifactPath string) string {
+	if rest, ok := strings.CutPrefix(artifactPath, "artifacts/"); ok && !localHasArtifactsDir(dir) {
+		artifactPath = rest
+	}
+	return filepath.Join(dir, filepath.FromSlash(artifactPath))
+}
+
+// localHasArtifactsDir reports whether the build folder dir has an artifacts directory
+func localHasArtifactsDir(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, "artifacts"))
+	return err == nil && info.IsDir()
+}
+
+// localRunDir converts the file:// URL of a run into its directory
+func localRunDir(runURL string) string {
+	return filepath.FromSlash(strings.TrimPrefix(runURL, "file://"))
+}
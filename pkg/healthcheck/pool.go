@@ -0,0 +1,35 @@
+package healthcheck
+
+import "sync"
+
+// DefaultParallelism is the number of concurrent artifact fetches used when none is configured
+const DefaultParallelism = 8
+
+// runParallel calls fn for every index in [0, n) using at most parallel concurrent workers.
+// Callers keep results deterministic by writing only to the slot owned by the index.
+func runParallel(n, parallel int, fn func(i int)) {
+	if parallel < 1 {
+		parallel = DefaultParallelism
+	}
+	if parallel > n {
+		parallel = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -8,20 +9,25 @@ import (
 	"time"
 )
 
+// JobRegexAliases are the built-in job regex aliases, extended by the config file
 var JobRegexAliases = map[string]string{
 	"main":        "sig-[a-zA-Z0-9_-]+$",
 	"1.6":         "release-1.6$",
 	"1.5":         "release-1.5$",
 	"1.4":         "release-1.4$",
 	"compute":     "sig-compute$|sig-compute-serial$|sig-compute-migrations$|sig-operator$|.*arm64.*",
-	"compute-1.6": "sig-compute-1.6$|sig-compute-serial-1.6$|sig-compute-migrations1-.6$|sig-operator1.6$|.*arm64.*-1.6$",
+	"compute-1.6": "sig-compute-1.6$|sig-compute-serial-1.6$|sig-compute-migrations-1.6$|sig-operator-1.6$|.*arm64.*-1.6$",
 	"network":     "sig-network$",
 	"storage":     "sig-storage$",
 }
 
 type ProcessorConfig struct {
+	Source               Source
+	QuarantinedTestsURL  string
+	QuarantineSource     string // KubeVirt checkout scanned for quarantined tests instead of the report
 	JobRegex             *regexp.Regexp
 	TestRegex            *regexp.Regexp
+	Labels               []string // Only include tests carrying one of these Ginkgo labels
 	DisplayOnlyURLs      bool
 	DisplayOnlyTestNames bool
 	DisplayFailures      bool
@@ -31,6 +37,7 @@ type ProcessorConfig struct {
 	TimePeriod           time.Duration
 	SuppressOutput       bool // Suppress all immediate output for JSON formatting
 	Summary              bool
+	Parallel             int // Number of failed runs whose artifacts are fetched concurrently
 }
 
 type ProcessorResult struct {
@@ -38,6 +45,16 @@ type ProcessorResult struct {
 	LaneRunFailures map[string][]Testcase
 }
 
+// jobFailure is a failed run of a job together with the artifacts fetched for it
+type jobFailure struct {
+	job        Job
+	failureURL string
+	skipped    bool // Outside of the configured time period
+	jobType    string
+	testsuite  *Testsuite
+	err        error
+}
+
 func ExtractLaneRunUUID(failureURL string) string {
 	parts := strings.Split(failureURL, "/")
 	if len(parts) > 0 {
@@ -46,33 +63,47 @@ func ExtractLaneRunUUID(failureURL string) string {
 	return ""
 }
 
-func ProcessFailures(results *Results, config ProcessorConfig) (*ProcessorResult, error) {
+func ProcessFailures(ctx context.Context, results *Results, config ProcessorConfig) (*ProcessorResult, error) {
 	result := &ProcessorResult{
 		FailedTests:     make(map[string][]Testcase),
 		LaneRunFailures: make(map[string][]Testcase),
 	}
 
 	// Fetch quarantined tests if checking is enabled
-	var quarantinedTests map[string]bool
+	var quarantinedTests []QuarantineEntry
 	if config.CheckQuarantine {
 		var err error
-		quarantinedTests, err = FetchQuarantinedTests()
+		quarantinedTests, err = LoadQuarantinedTests(ctx, config.QuarantinedTestsURL, config.QuarantineSource)
 		if err != nil {
 			// Don't fail the entire operation if quarantine check fails
 			fmt.Printf("Warning: Failed to fetch quarantined tests: %v\n", err)
-			quarantinedTests = make(map[string]bool)
+			quarantinedTests = []QuarantineEntry{}
 		}
 	}
 
+	var failures []jobFailure
 	for _, job := range results.Data.SIGRetests.FailedJobLeaderBoard {
 		if !config.JobRegex.MatchString(job.JobName) {
 			continue
 		}
 
 		for _, failureURL := range job.FailureURLs {
-			if err := processJobFailure(job, failureURL, config, result, quarantinedTests); err != nil {
-				return nil, err
-			}
+			failures = append(failures, jobFailure{job: job, failureURL: failureURL})
+		}
+	}
+
+	// Fetch artifacts concurrently, then process the failures in their original order
+	// so that output and the result maps are only ever touched by this goroutine
+	runParallel(len(failures), config.Parallel, func(i int) {
+		fetchJobFailure(ctx, &failures[i], config)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range failures {
+		if err := processJobFailure(&failures[i], config, result, quarantinedTests); err != nil {
+			return nil, err
 		}
 	}
 
@@ -80,16 +111,9 @@ func ProcessFailures(results *Results, config ProcessorConfig) (*ProcessorResult
 }
 
 // fetchJobTypeFromURL fetches the job type from prowjob.json for a given failure URL
-func fetchJobTypeFromURL(failureURL string) string {
-	// Convert prow URL to prowjob.json URL
-	prowjobURL := strings.Replace(failureURL, "prow.ci.kubevirt.io//view/gs", "storage.googleapis.com", 1)
-	if !strings.HasSuffix(prowjobURL, "/") {
-		prowjobURL += "/"
-	}
-	prowjobURL += "prowjob.json"
-
+func fetchJobTypeFromURL(ctx context.Context, source Source, failureURL string) string {
 	// Fetch job info
-	info, err := fetchProwJobInfo(prowjobURL)
+	info, err := source.FetchProwJob(ctx, &JobRun{URL: failureURL})
 	if err != nil {
 		// If we can't fetch job type, return empty string
 		return ""
@@ -98,34 +122,45 @@ func fetchJobTypeFromURL(failureURL string) string {
 	return info.JobType
 }
 
-func processJobFailure(job Job, failureURL string, config ProcessorConfig,
-	result *ProcessorResult, quarantinedTests map[string]bool) error {
-
+// fetchJobFailure fetches the job type and junit results of a failed run
+func fetchJobFailure(ctx context.Context, failure *jobFailure, config ProcessorConfig) {
 	// Check time filter if specified
 	if config.TimePeriod > 0 {
-		timestamp := extractTimestampFromURL(failureURL)
+		timestamp := extractTimestampFromURL(failure.failureURL)
 		if timestamp != "" && !IsWithinTimePeriod(timestamp, config.TimePeriod) {
-			return nil // Skip this failure as it's outside the time period
+			failure.skipped = true // Skip this failure as it's outside the time period
+			return
 		}
 	}
 
 	// Fetch job type from prowjob.json
-	jobType := fetchJobTypeFromURL(failureURL)
+	failure.jobType = fetchJobTypeFromURL(ctx, config.Source, failure.failureURL)
 
-	testsuite, err := fetchTestSuite(failureURL)
-	if err != nil {
-		return err
+	failure.testsuite, failure.err = fetchTestSuite(ctx, config.Source, failure.failureURL)
+}
+
+func processJobFailure(failure *jobFailure, config ProcessorConfig,
+	result *ProcessorResult, quarantinedTests []QuarantineEntry) error {
+	if failure.skipped {
+		return nil
+	}
+
+	if failure.err != nil {
+		return failure.err
 	}
 
-	if testsuite == nil {
-		return handleMissingTestsuite(job, failureURL, jobType, config, result, quarantinedTests)
+	if failure.testsuite == nil {
+		if len(config.Labels) > 0 {
+			return nil // Runs without test results carry no labels
+		}
+		return handleMissingTestsuite(failure.job, failure.failureURL, failure.jobType, config, result, quarantinedTests)
 	}
 
-	return processTestcases(testsuite, failureURL, jobType, config, result, quarantinedTests)
+	return processTestcases(failure.testsuite, failure.failureURL, failure.jobType, config, result, quarantinedTests)
 }
 
 func handleMissingTestsuite(job Job, failureURL string, jobType string, config ProcessorConfig,
-	result *ProcessorResult, _ map[string]bool) error {
+	result *ProcessorResult, _ []QuarantineEntry) error {
 	if config.DisplayOnlyURLs && !config.SuppressOutput {
 		fmt.Println(failureURL)
 		return nil
@@ -155,9 +190,9 @@ func handleMissingTestsuite(job Job, failureURL string, jobType string, config P
 }
 
 func processTestcases(testsuite *Testsuite, failureURL string, jobType string, config ProcessorConfig,
-	result *ProcessorResult, quarantinedTests map[string]bool) error {
+	result *ProcessorResult, quarantinedTests []QuarantineEntry) error {
 	for _, testcase := range testsuite.Testcase {
-		if testcase.Failure == nil || !config.TestRegex.MatchString(testcase.Name) {
+		if testcase.Failure == nil || !config.TestRegex.MatchString(testcase.Name) || !testcase.HasAnyLabel(config.Labels) {
 			continue
 		}
 
@@ -200,6 +235,7 @@ func processTestcase(testcase Testcase, config ProcessorConfig, result *Processo
 	if !config.SuppressOutput {
 		fmt.Println(testcase.Name)
 		if config.DisplayFailures {
+			printGinkgoDetails(testcase, "")
 			fmt.Printf("%s\n\n", testcase.Failure)
 		}
 		fmt.Printf("%s\n\n", testcase.URL)
@@ -210,24 +246,13 @@ func processTestcase(testcase Testcase, config ProcessorConfig, result *Processo
 }
 
 // isTestQuarantined checks if a test name matches any quarantined test
-func isTestQuarantined(testName string, quarantinedTests map[string]bool) bool {
-	// Direct match
-	if quarantinedTests[testName] {
-		return true
-	}
-
-	// Check for partial matches - test names in junit files often contain extra context
-	for quarantinedName := range quarantinedTests {
-		if strings.Contains(testName, quarantinedName) {
-			return true
-		}
-	}
-
-	return false
+func isTestQuarantined(testName string, quarantinedTests []QuarantineEntry) bool {
+	return FindQuarantineEntry(quarantinedTests, testName) != nil
 }
 
-// AnalyzeLaneRuns processes job runs and creates a summary
-func AnalyzeLaneRuns(runs []JobRun) (*LaneSummary, error) {
+// AnalyzeLaneRuns fetches the artifacts of each job run from the source, using up to
+// parallel concurrent fetches, and creates a summary
+func AnalyzeLaneRuns(ctx context.Context, source Source, runs []JobRun, parallel int) (*LaneSummary, error) {
 	summary := &LaneSummary{
 		TotalRuns:          len(runs),
 		TestFailures:       make(map[string]int),
@@ -240,12 +265,20 @@ func AnalyzeLaneRuns(runs []JobRun) (*LaneSummary, error) {
 	// Track failures per job type for calculating failure rates
 	jobTypeFailures := make(map[string]int)
 
+	// Fetch artifacts concurrently; each worker only writes to its own run
+	fetchErrors := make([]error, len(runs))
+	runParallel(len(runs), parallel, func(i int) {
+		fetchErrors[i] = fetchJobArtifacts(ctx, source, &runs[i])
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Analyze each job run
 	for i := range runs {
 		run := &runs[i]
 
-		// Fetch artifacts and analyze failures
-		if err := fetchJobArtifacts(run); err != nil {
+		if fetchErrors[i] != nil {
 			// Don't fail completely if one job fails to fetch
 			continue
 		}
@@ -319,6 +352,8 @@ func AnalyzeLaneRuns(runs []JobRun) (*LaneSummary, error) {
 
 	// Analyze failure patterns
 	summary.TopFailures = analyzeFailurePatterns(summary.TestFailures, len(summary.AllFailures))
+	summary.TestStats = calculateTestStats(runs)
+	summary.Flakes = DetectFlakes(runs)
 
 	// Calculate infrastructure failure rate based on categorized failures
 	infrastructureFailures := 0
@@ -433,6 +468,8 @@ func FilterLaneSummaryByJobType(summary *LaneSummary, jobType string) *LaneSumma
 
 	// Analyze failure patterns
 	filtered.TopFailures = analyzeFailurePatterns(filtered.TestFailures, len(filtered.AllFailures))
+	filtered.TestStats = calculateTestStats(filteredRuns)
+	filtered.Flakes = DetectFlakes(filteredRuns)
 
 	// Calculate infrastructure failure rate
 	infrastructureFailures := 0
@@ -453,6 +490,84 @@ func FilterLaneSummaryByJobType(summary *LaneSummary, jobType string) *LaneSumma
 	return filtered
 }
 
+// FilterLaneSummaryByLabels filters the test failures of a lane summary to the tests carrying
+// one of the given Ginkgo labels. Run counts and failure rates still describe the whole lane.
+func FilterLaneSummaryByLabels(summary *LaneSummary, labels []string) *LaneSummary {
+	if len(labels) == 0 {
+		return summary
+	}
+
+	filtered := *summary
+	filtered.Runs = make([]JobRun, len(summary.Runs))
+	filtered.TestFailures = make(map[string]int)
+	filtered.AllFailures = []Testcase{}
+
+	for i, run := range summary.Runs {
+		run.Failures = filterTestcasesByLabels(run.Failures, labels)
+		run.Passed = filterTestcasesByLabels(run.Passed, labels)
+		run.Skipped = filterTestcasesByLabels(run.Skipped, labels)
+		filtered.Runs[i] = run
+	}
+
+	// Infrastructure placeholders carry no labels and are dropped as well
+	for _, failure := range summary.AllFailures {
+		if failure.HasAnyLabel(labels) {
+			filtered.TestFailures[failure.Name]++
+			filtered.AllFailures = append(filtered.AllFailures, failure)
+		}
+	}
+
+	filtered.TopFailures = analyzeFailurePatterns(filtered.TestFailures, len(filtered.AllFailures))
+	filtered.InfrastructureFailureRate = 0
+	filtered.TestStats = calculateTestStats(filtered.Runs)
+	filtered.Flakes = DetectFlakes(filtered.Runs)
+
+	return &filtered
+}
+
+// filterTestcasesByLabels returns the testcases carrying one of the given Ginkgo labels
+func filterTestcasesByLabels(testcases []Testcase, labels []string) []Testcase {
+	var filtered []Testcase
+	for _, testcase := range testcases {
+		if testcase.HasAnyLabel(labels) {
+			filtered = append(filtered, testcase)
+		}
+	}
+	return filtered
+}
+
+// calculateTestStats counts the passes, failures and skips of every test across runs.
+// Runs without test results, such as infrastructure failures, do not count as executions.
+func calculateTestStats(runs []JobRun) map[string]TestStats {
+	stats := make(map[string]TestStats)
+	for _, run := range runs {
+		for _, testcase := range run.Passed {
+			s := stats[testcase.Name]
+			s.Passes++
+			stats[testcase.Name] = s
+		}
+		for _, testcase := range run.Failures {
+			s := stats[testcase.Name]
+			s.Failures++
+			stats[testcase.Name] = s
+		}
+		for _, testcase := range run.Skipped {
+			s := stats[testcase.Name]
+			s.Skips++
+			stats[testcase.Name] = s
+		}
+	}
+
+	for name, s := range stats {
+		s.Executions = s.Passes + s.Failures
+		if s.Executions > 0 {
+			s.PassRate = float64(s.Passes) / float64(s.Executions) * 100
+		}
+		stats[name] = s
+	}
+	return stats
+}
+
 // extractTimestampFromURL attempts to extract a timestamp from a Prow URL
 // For merge command URLs, we may need to fetch the job metadata to get the timestamp
 func extractTimestampFromURL(url string) string {
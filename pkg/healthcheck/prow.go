@@ -0,0 +1,758 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProwConfig describes the endpoints of a Prow deployment and the repository it tests
+type ProwConfig struct {
+	ProwURL     string // Prow deck frontend, e.g. https://prow.ci.kubevirt.io
+	GCSWebURL   string // gcsweb frontend used for directory listings
+	StorageURL  string // Direct object storage endpoint serving the artifacts
+	Bucket      string // Bucket holding job artifacts
+	Org         string // GitHub organization of the tested repository
+	Repo        string // GitHub repository name
+	CIHealthURL string // Base URL of the ci-health output
+}
+
+// DefaultProwConfig returns the configuration of the upstream KubeVirt Prow deployment
+func DefaultProwConfig() ProwConfig {
+	return ProwConfig{
+		ProwURL:     "https://prow.ci.kubevirt.io",
+		GCSWebURL:   "https://gcsweb.ci.kubevirt.io",
+		StorageURL:  "https://storage.googleapis.com",
+		Bucket:      "kubevirt-prow",
+		Org:         "kubevirt",
+		Repo:        "kubevirt",
+		CIHealthURL: "https://kubevirt.io/ci-health/output",
+	}
+}
+
+// HealthURL returns the ci-health results.json URL for the configured repository
+func (c ProwConfig) HealthURL() string {
+	return fmt.Sprintf("%s/%s/%s/results.json", strings.TrimSuffix(c.CIHealthURL, "/"), c.Org, c.Repo)
+}
+
+// QuarantinedTestsURL returns the quarantined tests report URL for the configured repository
+func (c ProwConfig) QuarantinedTestsURL() string {
+	return fmt.Sprintf("%s/%s/reports/quarantined-tests/%s/%s/index.html",
+		strings.TrimSuffix(c.StorageURL, "/"), c.Bucket, c.Org, c.Repo)
+}
+
+// ProwSource is a Source backed by a Prow deployment and its artifact bucket
+type ProwSource struct {
+	config ProwConfig
+	cache  *Cache
+}
+
+// NewProwSource creates a Source for the given Prow deployment. Artifacts of
+// finished builds are stored in cache unless it is nil.
+func NewProwSource(config ProwConfig, cache *Cache) *ProwSource {
+	config.ProwURL = strings.TrimSuffix(config.ProwURL, "/")
+	config.GCSWebURL = strings.TrimSuffix(config.GCSWebURL, "/")
+	config.StorageURL = strings.TrimSuffix(config.StorageURL, "/")
+
+	return &ProwSource{
+		config: config,
+		cache:  cache,
+	}
+}
+
+// ListRuns fetches runs from the presubmit, batch and periodic/postsubmit locations of a job
+func (s *ProwSource) ListRuns(ctx context.Context, jobName string, limit int, timePeriod time.Duration) ([]JobRun, error) {
+	return s.listRuns(ctx, jobName, limit, func(run JobRun) bool {
+		// If we can't parse the timestamp, include it to be safe
+		return timePeriod == 0 || IsWithinTimePeriod(run.Timestamp, timePeriod)
+	})
+}
+
+// ListRunsSince fetches the runs of a job whose build ID is at least buildID. Build IDs
+// grow over time, so paging through the job history stops at the first older build.
+func (s *ProwSource) ListRunsSince(ctx context.Context, jobName string, limit int, buildID string) ([]JobRun, error) {
+	return s.listRuns(ctx, jobName, limit, func(run JobRun) bool {
+		return compareBuildIDs(run.ID, buildID) >= 0
+	})
+}
+
+// listRuns fetches runs from the presubmit, batch and periodic/postsubmit locations of a
+// job, keeping the newest runs until the first one rejected by keep. A job without runs
+// in any location returns ErrNoJobHistory, unless a location could not be read.
+func (s *ProwSource) listRuns(ctx context.Context, jobName string, limit int, keep func(JobRun) bool) ([]JobRun, error) {
+	var allRuns []JobRun
+	var listErr error // First location that could not be read
+
+	// 1. Try pr-logs/directory - presubmit jobs (uses job-history API)
+	runs, err := s.fetchJobHistoryFromURL(ctx, s.jobHistoryURL("pr-logs/directory", jobName), limit, keep)
+	if err != nil {
+		listErr = err
+	}
+	allRuns = append(allRuns, runs...)
+
+	// 2. Try pr-logs/pull/batch - batch jobs (direct GCS scraping, no job-history API support)
+	// Note: We fetch all batch jobs and filter them since GCS listing doesn't support pagination
+	batchRuns, err := s.fetchBatchJobsFromGCS(ctx, jobName, limit)
+	if err != nil && listErr == nil {
+		listErr = err
+	}
+	for _, run := range batchRuns {
+		if keep(run) {
+			allRuns = append(allRuns, run)
+		}
+	}
+
+	// 3. Try logs - periodic/postsubmit jobs (uses job-history API)
+	runs, err = s.fetchJobHistoryFromURL(ctx, s.jobHistoryURL("logs", jobName), limit, keep)
+	if err != nil && listErr == nil {
+		listErr = err
+	}
+	allRuns = append(allRuns, runs...)
+
+	// If we collected runs from multiple sources, deduplicate by ID and limit to requested count
+	if len(allRuns) > 0 {
+		allRuns = deduplicateAndLimitRuns(allRuns, limit)
+		return allRuns, nil
+	}
+
+	// Don't report a cancelled crawl as a job without history
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	return nil, fmt.Errorf("%w in pr-logs/directory, pr-logs/pull/batch, or logs", ErrNoJobHistory)
+}
+
+// jobHistoryURL builds the Prow job-history URL for a job stored under the given bucket prefix
+func (s *ProwSource) jobHistoryURL(prefix, jobName string) string {
+	return fmt.Sprintf("%s/job-history/gs/%s/%s/%s", s.config.ProwURL, s.config.Bucket, prefix, jobName)
+}
+
+// fetchJobHistoryFromURL fetches job history from a specific base URL with pagination.
+// Pages list runs newest first, so pagination stops at the first run rejected by keep.
+func (s *ProwSource) fetchJobHistoryFromURL(ctx context.Context, baseURL string, limit int, keep func(JobRun) bool) ([]JobRun, error) {
+	var allRuns []JobRun
+	currentURL := baseURL
+
+	for len(allRuns) < limit {
+		// Fetch current page
+		body, err := s.get(ctx, currentURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch job history: %w", err)
+		}
+		if body == nil {
+			// No runs were ever stored in this location
+			break
+		}
+
+		// Parse this page's job runs
+		pageRuns, nextBuildID, err := parseJobHistoryPage(string(body), s.config.ProwURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// Add each run that is kept (up to our limit)
+		foundOldRuns := false
+		for _, run := range pageRuns {
+			if len(allRuns) >= limit {
+				break
+			}
+
+			if !keep(run) {
+				// This run is older than the ones we want, stop pagination
+				foundOldRuns = true
+				break
+			}
+
+			allRuns = append(allRuns, run)
+		}
+
+		// Stop if we found runs older than the ones we want or no more pages
+		if foundOldRuns || nextBuildID == "" {
+			break
+		}
+
+		// Prepare URL for next page
+		currentURL = fmt.Sprintf("%s?buildId=%s", baseURL, nextBuildID)
+	}
+
+	return allRuns, nil
+}
+
+// parseJobHistoryPage extracts job run information from a single Prow history HTML page
+func parseJobHistoryPage(htmlContent, prowURL string) ([]JobRun, string, error) {
+	var runs []JobRun
+
+	// Look for allBuilds JSON array in the JavaScript
+	re := regexp.MustCompile(`allBuilds\s*=\s*(\[.*?\]);`)
+	match := re.FindStringSubmatch(htmlContent)
+
+	if len(match) < 2 {
+		return runs, "", fmt.Errorf("could not find allBuilds JSON in page content")
+	}
+
+	// Parse the JSON array
+	var buildData []map[string]interface{}
+	if err := json.Unmarshal([]byte(match[1]), &buildData); err != nil {
+		return runs, "", fmt.Errorf("failed to parse builds JSON: %w", err)
+	}
+
+	var nextBuildID string
+	for _, build := range buildData {
+		// Extract build information
+		buildID, ok := build["ID"].(string)
+		if !ok {
+			continue
+		}
+
+		spyglassLink, ok := build["SpyglassLink"].(string)
+		if !ok {
+			continue
+		}
+
+		// Convert SpyglassLink to prow URL format
+		runURL := spyglassLink
+		if !strings.HasPrefix(runURL, "https://") {
+			if strings.HasPrefix(runURL, "/") {
+				runURL = prowURL + runURL
+			} else {
+				runURL = prowURL + "/" + runURL
+			}
+		}
+
+		// Extract timestamp if available
+		timestamp := ""
+		if started, ok := build["Started"].(string); ok {
+			timestamp = started
+		}
+
+		// The result is the normalized status (SUCCESS, FAILURE, PENDING, ...); the
+		// analysis replaces it with the state from prowjob.json
+		status := ""
+		if result, ok := build["Result"].(string); ok {
+			status = result
+		}
+
+		run := JobRun{
+			ID:        buildID,
+			URL:       runURL,
+			Status:    status,
+			Timestamp: timestamp,
+		}
+
+		runs = append(runs, run)
+
+		// Keep track of the last (oldest) buildID for pagination
+		nextBuildID = buildID
+	}
+
+	return runs, nextBuildID, nil
+}
+
+// fetchBatchJobsFromGCS fetches batch jobs directly from GCS by scraping the directory listing
+func (s *ProwSource) fetchBatchJobsFromGCS(ctx context.Context, jobName string, limit int) ([]JobRun, error) {
+	gcsURL := fmt.Sprintf("%s/gcs/%s/pr-logs/pull/batch/%s/", s.config.GCSWebURL, s.config.Bucket, jobName)
+
+	body, err := s.get(ctx, gcsURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch batch jobs from GCS: %w", err)
+	}
+	if body == nil {
+		return nil, nil // No batch jobs, not an error
+	}
+
+	// Parse directory listing to extract build IDs
+	// Format: <a href="/gcs/BUCKET/pr-logs/pull/batch/JOB_NAME/BUILD_ID/">
+	buildIDPattern := regexp.MustCompile(fmt.Sprintf(`href="/gcs/%s/pr-logs/pull/batch/%s/(\d+)/"`,
+		regexp.QuoteMeta(s.config.Bucket), regexp.QuoteMeta(jobName)))
+	matches := buildIDPattern.FindAllStringSubmatch(string(body), -1)
+
+	if len(matches) == 0 {
+		return nil, nil // No builds found
+	}
+
+	var runs []JobRun
+	for _, match := range matches {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if len(match) < 2 {
+			continue
+		}
+		buildID := match[1]
+
+		// Construct the job run
+		run := JobRun{
+			ID: buildID,
+			URL: fmt.Sprintf("%s/view/gs/%s/pr-logs/pull/batch/%s/%s",
+				s.config.ProwURL, s.config.Bucket, jobName, buildID),
+		}
+
+		// Fetch prowjob.json to get timestamp and job type
+		if data, err := s.FetchArtifact(ctx, &run, "prowjob.json"); err == nil && data != nil {
+			if prowJob, err := ParseProwJob(data); err == nil {
+				run.JobType = prowJob.JobType()
+				if !prowJob.Metadata.CreationTimestamp.IsZero() {
+					run.Timestamp = prowJob.Metadata.CreationTimestamp.UTC().Format(time.RFC3339)
+				}
+				run.ProwJob = prowJob
+			}
+		}
+
+		runs = append(runs, run)
+
+		if len(runs) >= limit {
+			break
+		}
+	}
+
+	return runs, nil
+}
+
+// ProwJobInfo contains information from prowjob.json
+type ProwJobInfo struct {
+	Status    string
+	JobType   string
+	CommitSet string   // Code the job tested, see Refs.CommitSet
+	ProwJob   *ProwJob // Full prowjob, nil when the source only knows the status
+}
+
+// FetchProwJob fetches the job status and type from prowjob.json
+func (s *ProwSource) FetchProwJob(ctx context.Context, run *JobRun) (*ProwJobInfo, error) {
+	body, err := s.FetchArtifact(ctx, run, "prowjob.json")
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		return nil, fmt.Errorf("prowjob.json not found")
+	}
+
+	return parseProwJobInfo(body)
+}
+
+// parseProwJobInfo extracts the job status and type from the content of prowjob.json
+func parseProwJobInfo(body []byte) (*ProwJobInfo, error) {
+	prowJob, err := ParseProwJob(body)
+	if err != nil {
+		return nil, err
+	}
+	if prowJob.Status.State == "" {
+		return nil, fmt.Errorf("could not find status.state in prowjob.json")
+	}
+
+	return &ProwJobInfo{
+		Status:    prowJob.Status.State,
+		JobType:   prowJob.JobType(),
+		CommitSet: prowJob.CommitSet(),
+		ProwJob:   prowJob,
+	}, nil
+}
+
+// FetchArtifact fetches a file from the run's directory in the artifact bucket.
+// Artifacts of finished builds are served from and stored in the cache.
+func (s *ProwSource) FetchArtifact(ctx context.Context, run *JobRun, path string) ([]byte, error) {
+	runPath, err := s.runPath(run.URL)
+	if err != nil {
+		return nil, err
+	}
+	objectPath := runPath + "/" + strings.TrimPrefix(path, "/")
+	runKey := s.cacheKey(runPath)
+	key := s.cacheKey(objectPath)
+
+	if s.cache != nil {
+		if data, ok := s.cache.Get(key); ok {
+			return data, nil
+		}
+	}
+
+	data, err := s.get(ctx, s.config.StorageURL+"/"+objectPath)
+	if err != nil || data == nil || s.cache == nil {
+		return data, err
+	}
+
+	if s.isFinished(runKey, path, data) {
+		// A failed write only costs a download next time
+		s.cache.Put(key, data)
+	}
+
+	return data, nil
+}
+
+// isFinished reports whether the build cached under runKey has completed, so that its
+// artifacts never change. prowjob.json is only cached once it records a final state, which
+// makes its presence in the cache the marker for the remaining artifacts of the build.
+func (s *ProwSource) isFinished(runKey, path string, data []byte) bool {
+	if strings.TrimPrefix(path, "/") != "prowjob.json" {
+		return s.cache.Has(runKey + "/prowjob.json")
+	}
+
+	prowJob, err := ParseProwJob(data)
+	if err != nil {
+		return false
+	}
+
+	switch prowJob.Status.State {
+	case "", "pending", "triggered":
+		return false
+	default:
+		return true
+	}
+}
+
+// ListArtifacts lists the run's files matching glob through the GCS JSON API. The
+// listing of a finished build is cached like its artifacts.
+func (s *ProwSource) ListArtifacts(ctx context.Context, run *JobRun, glob string) ([]string, error) {
+	runPath, err := s.runPath(run.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// The listing is stored next to the build's artifacts under a name no artifact uses
+	runKey := s.cacheKey(runPath)
+	cacheKey := runKey + "/.healthcheck-listing/" + url.PathEscape(glob)
+	if s.cache != nil {
+		if data, ok := s.cache.Get(cacheKey); ok {
+			var paths []string
+			if json.Unmarshal(data, &paths) == nil {
+				return paths, nil
+			}
+		}
+	}
+
+	// The run directory is the prefix below the bucket name
+	bucketPrefix := strings.TrimPrefix(runPath, s.config.Bucket+"/") + "/"
+	var paths []string
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", bucketPrefix)
+		query.Set("matchGlob", bucketPrefix+glob)
+		query.Set("fields", "items(name),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.config.StorageURL, url.PathEscape(s.config.Bucket), query.Encode())
+
+		body, err := s.get(ctx, listURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		if body == nil {
+			return nil, fmt.Errorf("failed to list artifacts: bucket %s not found", s.config.Bucket)
+		}
+
+		var page struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse artifact listing: %w", err)
+		}
+
+		for _, item := range page.Items {
+			paths = append(paths, strings.TrimPrefix(item.Name, bucketPrefix))
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+
+	if s.cache != nil && s.cache.Has(runKey+"/prowjob.json") {
+		if data, err := json.Marshal(paths); err == nil {
+			s.cache.Put(cacheKey, data)
+		}
+	}
+
+	return paths, nil
+}
+
+// listPrefixes lists the immediate subdirectories of a bucket directory through the GCS
+// JSON API, as names without the directory and trailing slash
+func (s *ProwSource) listPrefixes(ctx context.Context, dir string) ([]string, error) {
+	dir = strings.Trim(dir, "/") + "/"
+	var names []string
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", dir)
+		query.Set("delimiter", "/")
+		query.Set("fields", "prefixes,nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.config.StorageURL, url.PathEscape(s.config.Bucket), query.Encode())
+
+		body, err := s.get(ctx, listURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+		if body == nil {
+			return nil, fmt.Errorf("failed to list %s: bucket %s not found", dir, s.config.Bucket)
+		}
+
+		var page struct {
+			Prefixes      []string `json:"prefixes"`
+			NextPageToken string   `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse listing of %s: %w", dir, err)
+		}
+
+		for _, prefix := range page.Prefixes {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(prefix, dir), "/"))
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return names, nil
+}
+
+// DiscoverLanes lists the lanes with runs in the bucket, keyed by the directory they
+// were found in: pr-logs/directory for presubmits, logs for periodics and postsubmits
+func (s *ProwSource) DiscoverLanes(ctx context.Context) (map[string]string, error) {
+	lanes := make(map[string]string)
+	for _, dir := range []string{"pr-logs/directory", "logs"} {
+		names, err := s.listPrefixes(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if _, ok := lanes[name]; !ok {
+				lanes[name] = dir
+			}
+		}
+	}
+	return lanes, nil
+}
+
+// ActiveLanes lists the lanes of the configured repository matching match that started a
+// run since the given time. Prow updates the latest-build.txt of a lane whenever one of
+// its runs starts, so listing those with their update time leaves out stale lanes without
+// a request per lane; the repository of the remaining lanes is read from their latest run.
+func (s *ProwSource) ActiveLanes(ctx context.Context, since time.Time, match func(string) bool) ([]string, error) {
+	type lane struct{ name, dir string }
+	var candidates []lane
+	seen := make(map[string]bool)
+	for _, dir := range []string{"pr-logs/directory", "logs"} {
+		updated, err := s.listLatestBuilds(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		for name, at := range updated {
+			if !seen[name] && match(name) && !at.Before(since) {
+				seen[name] = true
+				candidates = append(candidates, lane{name: name, dir: dir})
+			}
+		}
+	}
+
+	ofRepo := make([]bool, len(candidates))
+	runParallel(len(candidates), DefaultParallelism, func(i int) {
+		ofRepo[i] = s.laneOfRepo(ctx, candidates[i].dir, candidates[i].name)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var lanes []string
+	for i, candidate := range candidates {
+		if ofRepo[i] {
+			lanes = append(lanes, candidate.name)
+		}
+	}
+	sort.Strings(lanes)
+	return lanes, nil
+}
+
+// listLatestBuilds lists the latest-build.txt of every lane below a bucket directory
+// through the GCS JSON API, returning when each was last updated by lane
+func (s *ProwSource) listLatestBuilds(ctx context.Context, dir string) (map[string]time.Time, error) {
+	dir = strings.Trim(dir, "/") + "/"
+	updated := make(map[string]time.Time)
+	pageToken := ""
+	for {
+		query := url.Values{}
+		query.Set("prefix", dir)
+		query.Set("matchGlob", dir+"*/latest-build.txt")
+		query.Set("fields", "items(name,updated),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		listURL := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.config.StorageURL, url.PathEscape(s.config.Bucket), query.Encode())
+
+		body, err := s.get(ctx, listURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list lanes of %s: %w", dir, err)
+		}
+		if body == nil {
+			return nil, fmt.Errorf("failed to list lanes of %s: bucket %s not found", dir, s.config.Bucket)
+		}
+
+		var page struct {
+			Items []struct {
+				Name    string    `json:"name"`
+				Updated time.Time `json:"updated"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, fmt.Errorf("failed to parse lanes of %s: %w", dir, err)
+		}
+
+		for _, item := range page.Items {
+			name := strings.TrimSuffix(strings.TrimPrefix(item.Name, dir), "/latest-build.txt")
+			updated[name] = item.Updated
+		}
+
+		if page.NextPageToken == "" {
+			break
+		}
+		pageToken = page.NextPageToken
+	}
+	return updated, nil
+}
+
+// laneOfRepo reports whether the latest run of a lane tested the configured repository.
+// Lanes whose latest run cannot be read are kept, so that their errors are reported by
+// the analysis instead of the lanes silently missing.
+func (s *ProwSource) laneOfRepo(ctx context.Context, dir, name string) bool {
+	base := fmt.Sprintf("%s/%s/%s/%s", s.config.StorageURL, s.config.Bucket, dir, name)
+	latest, err := s.get(ctx, base+"/latest-build.txt")
+	if err != nil || latest == nil {
+		return err != nil
+	}
+	buildID := strings.TrimSpace(string(latest))
+
+	runPath := dir + "/" + name + "/" + buildID
+	if dir == "pr-logs/directory" {
+		// Presubmit runs are stored under the pull request, pointed to by <build>.txt
+		link, err := s.get(ctx, base+"/"+buildID+".txt")
+		if err != nil || link == nil {
+			return err != nil
+		}
+		runPath = strings.TrimPrefix(strings.TrimSpace(string(link)), "gs://"+s.config.Bucket+"/")
+	}
+	if strings.Contains(runPath, fmt.Sprintf("pr-logs/pull/%s_%s/", s.config.Org, s.config.Repo)) {
+		return true
+	}
+
+	run := JobRun{ID: buildID, URL: fmt.Sprintf("%s/view/gs/%s/%s", s.config.ProwURL, s.config.Bucket, runPath)}
+	info, err := s.FetchProwJob(ctx, &run)
+	if err != nil {
+		return true
+	}
+	refs := info.ProwJob.Spec.ExtraRefs
+	if info.ProwJob.Spec.Refs != nil {
+		refs = append(refs, *info.ProwJob.Spec.Refs)
+	}
+	for _, ref := range refs {
+		if ref.Org == s.config.Org && ref.Repo == s.config.Repo {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPullRequestRuns lists the runs of every lane that tested a pull request, found
+// under pr-logs/pull/<org>_<repo>/<number>/ in the bucket, keyed by lane
+func (s *ProwSource) ListPullRequestRuns(ctx context.Context, number int) (map[string][]JobRun, error) {
+	prDir := fmt.Sprintf("pr-logs/pull/%s_%s/%d", s.config.Org, s.config.Repo, number)
+	lanes, err := s.listPrefixes(ctx, prDir)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make(map[string][]JobRun)
+	for _, lane := range lanes {
+		buildIDs, err := s.listPrefixes(ctx, prDir+"/"+lane)
+		if err != nil {
+			return nil, err
+		}
+		for _, buildID := range buildIDs {
+			runs[lane] = append(runs[lane], JobRun{
+				ID:  buildID,
+				URL: fmt.Sprintf("%s/view/gs/%s/%s/%s/%s", s.config.ProwURL, s.config.Bucket, prDir, lane, buildID),
+			})
+		}
+	}
+	return runs, nil
+}
+
+// FetchBuildLog fetches the build-log.txt of a run
+func (s *ProwSource) FetchBuildLog(ctx context.Context, run *JobRun) ([]byte, error) {
+	body, err := s.FetchArtifact(ctx, run, "build-log.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch build log: %w", err)
+	}
+	if body == nil {
+		return nil, fmt.Errorf("build log not found")
+	}
+	return body, nil
+}
+
+// runPath converts a Prow run URL into the bucket path of the run's directory
+func (s *ProwSource) runPath(runURL string) (string, error) {
+	// Example: https://prow.ci.kubevirt.io//view/gs/kubevirt-prow/pr-logs/pull/kubevirt_kubevirt/15434/pull-kubevirt-e2e-arm64/1955736656627634176
+	// Becomes: kubevirt-prow/pr-logs/pull/kubevirt_kubevirt/15434/pull-kubevirt-e2e-arm64/1955736656627634176
+	idx := strings.Index(runURL, "/view/gs/")
+	if idx == -1 {
+		return "", fmt.Errorf("unable to extract artifact path from run URL: %s", runURL)
+	}
+	return strings.Trim(runURL[idx+len("/view/gs/"):], "/"), nil
+}
+
+// cacheKey returns the cache key of a bucket path. Keys start with the storage host, so
+// deployments whose buckets share a name do not share cache entries.
+func (s *ProwSource) cacheKey(bucketPath string) string {
+	host := s.config.StorageURL
+	if u, err := url.Parse(s.config.StorageURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return host + "/" + bucketPath
+}
+
+// get fetches url and returns the response body. A 404 returns nil data and a nil error.
+func (s *ProwSource) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+
+	resp, err := HTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status code %d", url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s body: %w", url, err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,48 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchArtifactCachePerStorageHost(t *testing.T) {
+	withTransport(t, http.DefaultTransport)
+	cache := NewCache(t.TempDir())
+
+	// Two deployments with a bucket of the same name
+	newSource := func(state string) *ProwSource {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/b/logs/periodic-e2e/1/prowjob.json" {
+				http.NotFound(w, r)
+				return
+			}
+			io.WriteString(w, `{"status": {"state": "`+state+`"}}`)
+		}))
+		t.Cleanup(server.Close)
+
+		config := DefaultProwConfig()
+		config.ProwURL, config.StorageURL, config.Bucket = server.URL, server.URL, "b"
+		return NewProwSource(config, cache)
+	}
+	sources := map[string]*ProwSource{"success": newSource("success"), "failure": newSource("failure")}
+
+	for i := 0; i < 2; i++ {
+		for state, source := range sources {
+			run := &JobRun{URL: source.config.ProwURL + "/view/gs/b/logs/periodic-e2e/1"}
+			data, err := source.FetchArtifact(context.Background(), run, "prowjob.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+			prowJob, err := ParseProwJob(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prowJob.Status.State != state {
+				t.Errorf("FetchArtifact() from %s returned state %q, want %q", source.config.StorageURL, prowJob.Status.State, state)
+			}
+		}
+	}
+}
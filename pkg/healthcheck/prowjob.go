@@ -0,0 +1,141 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// jobTypeLabel is the prowjob label holding the job type
+const jobTypeLabel = "prow.k8s.io/type"
+
+// ProwJob is the part of a run's prowjob.json the tool uses
+type ProwJob struct {
+	Metadata ProwJobMetadata `json:"metadata"`
+	Spec     ProwJobSpec     `json:"spec"`
+	Status   ProwJobStatus   `json:"status"`
+}
+
+// ProwJobMetadata is the Kubernetes object metadata of a prowjob
+type ProwJobMetadata struct {
+	Name              string            `json:"name,omitempty"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	Annotations       map[string]string `json:"annotations,omitempty"`
+}
+
+// ProwJobSpec describes what a prowjob tested and where it ran
+type ProwJobSpec struct {
+	Type      string `json:"type,omitempty"` // presubmit, batch, postsubmit or periodic
+	Job       string `json:"job,omitempty"`
+	Cluster   string `json:"cluster,omitempty"` // Build cluster the job's pod ran in
+	Refs      *Refs  `json:"refs,omitempty"`
+	ExtraRefs []Refs `json:"extra_refs,omitempty"`
+}
+
+// Refs are the repository, base commit and pull requests a prowjob checked out
+type Refs struct {
+	Org     string `json:"org"`
+	Repo    string `json:"repo"`
+	BaseRef string `json:"base_ref,omitempty"`
+	BaseSHA string `json:"base_sha,omitempty"`
+	Pulls   []Pull `json:"pulls,omitempty"`
+}
+
+// Pull is a pull request merged on top of the base commit
+type Pull struct {
+	Number     int    `json:"number"`
+	Author     string `json:"author"`
+	SHA        string `json:"sha"`
+	Title      string `json:"title,omitempty"`
+	HeadRef    string `json:"head_ref,omitempty"`
+	Link       string `json:"link,omitempty"`
+	CommitLink string `json:"commit_link,omitempty"`
+	AuthorLink string `json:"author_link,omitempty"`
+}
+
+// ProwJobStatus is the outcome of a prowjob
+type ProwJobStatus struct {
+	State          string     `json:"state"` // triggered, pending, success, failure, aborted or error
+	Description    string     `json:"description,omitempty"`
+	StartTime      time.Time  `json:"startTime"`
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	URL            string     `json:"url,omitempty"`
+	BuildID        string     `json:"build_id,omitempty"`
+}
+
+// ParseProwJob parses the content of prowjob.json
+func ParseProwJob(data []byte) (*ProwJob, error) {
+	var prowJob ProwJob
+	if err := json.Unmarshal(data, &prowJob); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal prowjob.json: %w", err)
+	}
+	return &prowJob, nil
+}
+
+// JobType returns the job type from the prowjob labels, falling back to the spec
+func (p *ProwJob) JobType() string {
+	if jobType := p.Metadata.Labels[jobTypeLabel]; jobType != "" {
+		return jobType
+	}
+	return p.Spec.Type
+}
+
+// Started returns when the job started, or when it was created if it never started
+func (p *ProwJob) Started() time.Time {
+	if !p.Status.StartTime.IsZero() {
+		return p.Status.StartTime
+	}
+	return p.Metadata.CreationTimestamp
+}
+
+// Duration returns how long the job ran, or 0 while it has not completed
+func (p *ProwJob) Duration() time.Duration {
+	if p.Status.CompletionTime == nil || p.Status.StartTime.IsZero() {
+		return 0
+	}
+	return p.Status.CompletionTime.Sub(p.Status.StartTime)
+}
+
+// TestedRefs returns the refs of the tested repository: spec.refs, or for periodics
+// without them the first of spec.extra_refs. It returns nil when neither is set.
+func (p *ProwJob) TestedRefs() *Refs {
+	if p.Spec.Refs != nil {
+		return p.Spec.Refs
+	}
+	if len(p.Spec.ExtraRefs) > 0 {
+		return &p.Spec.ExtraRefs[0]
+	}
+	return nil
+}
+
+// CommitSet identifies the code the job tested, see Refs.CommitSet
+func (p *ProwJob) CommitSet() string {
+	if refs := p.TestedRefs(); refs != nil {
+		return refs.CommitSet()
+	}
+	return ""
+}
+
+// CommitSet identifies the code checked out for the refs: the repository, the base SHA
+// and the number:sha of every pull request merged on top, in pull request order. Runs
+// with the same commit set tested identical code, whether they were retests, batches or
+// presubmits. It is empty when the base SHA is unknown.
+func (r *Refs) CommitSet() string {
+	if r.BaseSHA == "" {
+		return ""
+	}
+	key := fmt.Sprintf("%s/%s@%s", r.Org, r.Repo, r.BaseSHA)
+	if len(r.Pulls) > 0 {
+		pulls := append([]Pull{}, r.Pulls...)
+		sort.Slice(pulls, func(i, j int) bool { return pulls[i].Number < pulls[j].Number })
+		shas := make([]string, len(pulls))
+		for i, pull := range pulls {
+			shas[i] = fmt.Sprintf("%d:%s", pull.Number, pull.SHA)
+		}
+		key += "+" + strings.Join(shas, ",")
+	}
+	return key
+}
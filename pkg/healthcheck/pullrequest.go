@@ -0,0 +1,298 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pullRequestSource is implemented by sources that can find the runs of a pull request
+type pullRequestSource interface {
+	ListPullRequestRuns(ctx context.Context, number int) (map[string][]JobRun, error)
+}
+
+// PullRequestConfig controls how the runs of a pull request are compared with other runs
+type PullRequestConfig struct {
+	TimePeriod   time.Duration // Window in which other runs of the same lanes are compared
+	CompareLimit int           // Maximum number of other runs fetched per lane
+	Parallel     int           // Number of runs whose artifacts are fetched concurrently
+}
+
+// PullRequestReport is the CI history of a pull request across all lanes it ran on
+type PullRequestReport struct {
+	Number      int               `json:"number"`
+	Author      string            `json:"author,omitempty"`
+	HeadSHA     string            `json:"head_sha,omitempty"` // SHA tested by the newest run
+	Lanes       []PullRequestLane `json:"lanes"`
+	FailedTests []PullRequestTest `json:"failed_tests"` // Most frequently failing first
+	Compared    string            `json:"compared_window"`
+	LaneErrors  []LaneError       `json:"lane_errors"` // Lanes whose other runs could not be fetched for the comparison
+}
+
+// PullRequestLane is the attempt history of a pull request on one lane
+type PullRequestLane struct {
+	JobName      string               `json:"job_name"`
+	LatestStatus string               `json:"latest_status"`
+	Attempts     []PullRequestAttempt `json:"attempts"` // Newest first
+}
+
+// PullRequestAttempt is a single run of a lane for the pull request
+type PullRequestAttempt struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Status      string   `json:"status"`
+	Timestamp   string   `json:"timestamp"`
+	SHA         string   `json:"sha,omitempty"` // Head SHA of the pull request the attempt tested
+	FailedTests []string `json:"failed_tests,omitempty"`
+}
+
+// PullRequestTest is a test that failed on the pull request, together with its failures
+// elsewhere. A test that also fails on other pull requests or in periodics in the same
+// window is probably not caused by the pull request.
+type PullRequestTest struct {
+	TestName         string   `json:"test_name"`
+	Lanes            []string `json:"lanes"`
+	Failures         int      `json:"failures"` // Failed attempts of the pull request
+	OtherPRs         []int    `json:"other_prs,omitempty"`
+	PeriodicFailures int      `json:"periodic_failures"`
+	ElsewhereURLs    []string `json:"elsewhere_urls,omitempty"` // Failed runs without this pull request
+	FailingElsewhere bool     `json:"failing_elsewhere"`
+	UncomparedLanes  []string `json:"uncompared_lanes,omitempty"` // Lanes to compare with whose runs could not be fetched
+}
+
+// FetchPullRequestRuns returns the runs of every lane that tested a pull request, keyed by lane
+func FetchPullRequestRuns(ctx context.Context, source Source, number int) (map[string][]JobRun, error) {
+	prSource, ok := source.(pullRequestSource)
+	if !ok {
+		return nil, fmt.Errorf("listing the runs of a pull request is not supported by this source")
+	}
+	runs, err := prSource.ListPullRequestRuns(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list runs of pull request %d: %w", number, err)
+	}
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no runs found for pull request %d", number)
+	}
+	return runs, nil
+}
+
+// AnalyzePullRequest fetches the results of every run of a pull request and checks
+// whether its failed tests also failed in other runs of the same lanes, or in the
+// periodic counterpart of each lane, within config.TimePeriod. Lanes whose runs cannot
+// be fetched for the comparison are listed in LaneErrors and in the UncomparedLanes of
+// the tests that failed on them, since those tests may be failing elsewhere.
+func AnalyzePullRequest(ctx context.Context, source Source, number int, config PullRequestConfig) (*PullRequestReport, error) {
+	laneRuns, err := FetchPullRequestRuns(ctx, source, number)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &PullRequestReport{Number: number, Compared: formatPeriod(config.TimePeriod), LaneErrors: []LaneError{}}
+	tests := make(map[string]*PullRequestTest)
+	var newest JobRun
+
+	lanes := make([]string, 0, len(laneRuns))
+	for lane := range laneRuns {
+		lanes = append(lanes, lane)
+	}
+	sort.Strings(lanes)
+
+	for _, lane := range lanes {
+		summary, err := AnalyzeLaneRuns(ctx, source, laneRuns[lane], config.Parallel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to analyze runs of %s: %w", lane, err)
+		}
+		runs := summary.Runs
+		sortRunsNewestFirst(runs)
+
+		prLane := PullRequestLane{JobName: lane}
+		for _, run := range runs {
+			attempt := PullRequestAttempt{ID: run.ID, URL: run.URL, Status: run.Status, Timestamp: run.Timestamp}
+			if pull := run.pull(number); pull != nil {
+				attempt.SHA = pull.SHA
+			}
+			for _, failure := range run.Failures {
+				if containsString(attempt.FailedTests, failure.Name) {
+					continue
+				}
+				attempt.FailedTests = append(attempt.FailedTests, failure.Name)
+
+				test, ok := tests[failure.Name]
+				if !ok {
+					test = &PullRequestTest{TestName: failure.Name}
+					tests[failure.Name] = test
+				}
+				test.Failures++
+				if !containsString(test.Lanes, lane) {
+					test.Lanes = append(test.Lanes, lane)
+				}
+			}
+			prLane.Attempts = append(prLane.Attempts, attempt)
+
+			if run.Timestamp > newest.Timestamp {
+				newest = run
+			}
+		}
+		if len(prLane.Attempts) > 0 {
+			prLane.LatestStatus = prLane.Attempts[0].Status
+		}
+		report.Lanes = append(report.Lanes, prLane)
+	}
+	if pull := newest.pull(number); pull != nil {
+		report.HeadSHA = pull.SHA
+		report.Author = pull.Author
+	}
+
+	// Compare the failed tests with the other runs of the lanes they failed on
+	compareLanes := make(map[string]bool)
+	for _, test := range tests {
+		for _, lane := range test.Lanes {
+			compareLanes[lane] = true
+		}
+	}
+	for _, lane := range lanes {
+		if !compareLanes[lane] {
+			continue
+		}
+		for _, compareLane := range []string{lane, periodicLaneName(lane)} {
+			if compareLane == "" {
+				continue
+			}
+			err := comparePullRequestFailures(ctx, source, number, compareLane, tests, config)
+			if err == nil {
+				continue
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			report.LaneErrors = append(report.LaneErrors, LaneError{JobName: compareLane, Error: err.Error()})
+			for _, test := range tests {
+				if containsString(test.Lanes, lane) {
+					test.UncomparedLanes = append(test.UncomparedLanes, compareLane)
+				}
+			}
+		}
+	}
+
+	for _, test := range tests {
+		sort.Ints(test.OtherPRs)
+		test.FailingElsewhere = len(test.OtherPRs) > 0 || test.PeriodicFailures > 0 || len(test.ElsewhereURLs) > 0
+		report.FailedTests = append(report.FailedTests, *test)
+	}
+	sort.Slice(report.FailedTests, func(i, j int) bool {
+		if report.FailedTests[i].Failures != report.FailedTests[j].Failures {
+			return report.FailedTests[i].Failures > report.FailedTests[j].Failures
+		}
+		return report.FailedTests[i].TestName < report.FailedTests[j].TestName
+	})
+
+	return report, nil
+}
+
+// comparePullRequestFailures records the failures of the pull request's failed tests in
+// the runs of lane that did not test the pull request. Lanes without runs in the window,
+// such as missing periodic counterparts, are skipped.
+func comparePullRequestFailures(ctx context.Context, source Source, number int, lane string, tests map[string]*PullRequestTest, config PullRequestConfig) error {
+	runs, err := FetchJobHistoryWithTimePeriod(ctx, source, lane, config.TimePeriod, config.CompareLimit)
+	if errors.Is(err, ErrNoJobHistory) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	summary, err := AnalyzeLaneRuns(ctx, source, runs, config.Parallel)
+	if err != nil {
+		return fmt.Errorf("failed to analyze runs: %w", err)
+	}
+
+	for _, run := range summary.Runs {
+		if run.pull(number) != nil || strings.Contains(run.URL, fmt.Sprintf("/%d/", number)) {
+			continue // A run of this pull request, or a batch including it
+		}
+		for _, failure := range run.Failures {
+			test, ok := tests[failure.Name]
+			if !ok {
+				continue
+			}
+			if containsString(test.ElsewhereURLs, run.URL) {
+				continue
+			}
+			test.ElsewhereURLs = append(test.ElsewhereURLs, run.URL)
+			if run.JobType == "periodic" {
+				test.PeriodicFailures++
+				continue
+			}
+			if refs := run.testedRefs(); refs != nil {
+				for _, pull := range refs.Pulls {
+					if !containsInt(test.OtherPRs, pull.Number) {
+						test.OtherPRs = append(test.OtherPRs, pull.Number)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// periodicLaneName returns the periodic lane running the same tests as a presubmit lane,
+// following the pull-kubevirt-* / periodic-kubevirt-* naming, or "" for other lanes
+func periodicLaneName(lane string) string {
+	if !strings.HasPrefix(lane, "pull-") {
+		return ""
+	}
+	return "periodic-" + strings.TrimPrefix(lane, "pull-")
+}
+
+// testedRefs returns the refs the run tested, or nil when its prowjob is unknown
+func (r *JobRun) testedRefs() *Refs {
+	if r.ProwJob == nil {
+		return nil
+	}
+	return r.ProwJob.TestedRefs()
+}
+
+// pull returns the pull request with the given number among the refs the run tested
+func (r *JobRun) pull(number int) *Pull {
+	refs := r.testedRefs()
+	if refs == nil {
+		return nil
+	}
+	for i := range refs.Pulls {
+		if refs.Pulls[i].Number == number {
+			return &refs.Pulls[i]
+		}
+	}
+	return nil
+}
+
+// sortRunsNewestFirst orders runs by timestamp, then by build ID, newest first
+func sortRunsNewestFirst(runs []JobRun) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		if runs[i].Timestamp != runs[j].Timestamp {
+			return runs[i].Timestamp > runs[j].Timestamp
+		}
+		return compareBuildIDs(runs[i].ID, runs[j].ID) > 0
+	})
+}
+
+// formatPeriod formats a time period the way ParseTimePeriod accepts it, in days when possible
+func formatPeriod(period time.Duration) string {
+	if period > 0 && period%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", period/(24*time.Hour))
+	}
+	return period.String()
+}
+
+// containsInt reports whether values contains value
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
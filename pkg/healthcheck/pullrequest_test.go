@@ -0,0 +1,57 @@
+package healthcheck
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pullRequestTestSource serves fixed runs as the runs of a pull request
+type pullRequestTestSource struct {
+	unreachableSource
+	runs map[string][]JobRun
+}
+
+func (s pullRequestTestSource) ListPullRequestRuns(ctx context.Context, number int) (map[string][]JobRun, error) {
+	return s.runs, nil
+}
+
+func TestAnalyzePullRequestReportsUncomparedLanes(t *testing.T) {
+	withNow(t, time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC))
+
+	const lane = "pull-kubevirt-e2e-k8s-1.33-sig-compute"
+	local := NewLocalSource("testdata/quarantine")
+	runs, err := local.ListRuns(context.Background(), lane, 100, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed []JobRun
+	for _, run := range runs {
+		if run.ID == "1953300000000000001" {
+			failed = append(failed, run)
+		}
+	}
+
+	// Other runs of the lane cannot be listed, and it has no periodic counterpart
+	source := pullRequestTestSource{
+		unreachableSource: unreachableSource{Source: local, lane: lane},
+		runs:              map[string][]JobRun{lane: failed},
+	}
+	report, err := AnalyzePullRequest(context.Background(), source, 1234, PullRequestConfig{TimePeriod: 14 * 24 * time.Hour, CompareLimit: 100, Parallel: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.LaneErrors) != 1 || report.LaneErrors[0].JobName != lane {
+		t.Errorf("LaneErrors = %+v, want only %s", report.LaneErrors, lane)
+	}
+	if len(report.FailedTests) != 1 {
+		t.Fatalf("FailedTests = %+v, want one", report.FailedTests)
+	}
+	test := report.FailedTests[0]
+	if test.FailingElsewhere || !reflect.DeepEqual(test.UncomparedLanes, []string{lane}) {
+		t.Errorf("failing elsewhere %v, uncompared lanes %v; want not failing elsewhere with %s uncompared",
+			test.FailingElsewhere, test.UncomparedLanes, lane)
+	}
+}
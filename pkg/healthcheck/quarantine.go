@@ -0,0 +1,263 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// QuarantineLanePattern matches the lanes running quarantined tests
+const QuarantineLanePattern = "quarantine"
+
+// maxOutcomeURLs is the number of run URLs kept as evidence per outcome
+const maxOutcomeURLs = 10
+
+// QuarantineConfig controls how quarantined and flaky tests are evaluated
+type QuarantineConfig struct {
+	TimePeriod     time.Duration // Window the runs are taken from
+	RunLimit       int           // Maximum number of runs fetched per lane
+	Parallel       int           // Number of runs fetched concurrently, shared by the lanes
+	MinExecutions  int           // Passes a quarantined test needs since QuarantinedTest.StableSince to be considered stable
+	FlakeThreshold float64       // Failure percentage above which a flaky, non-quarantined test should be quarantined
+}
+
+// QuarantineReport measures how quarantined tests behave in quarantine and regular lanes,
+// and which other tests are flaky enough to be quarantined
+type QuarantineReport struct {
+	Window          string                `json:"window"`
+	QuarantineLanes []string              `json:"quarantine_lanes"`
+	RegularLanes    []string              `json:"regular_lanes"`
+	Quarantined     []QuarantinedTest     `json:"quarantined"`      // One entry per quarantined test name
+	FlakyCandidates []QuarantineCandidate `json:"flaky_candidates"` // Non-quarantined tests above the flake threshold, flakiest first
+	LaneErrors      []LaneError           `json:"lane_errors"`      // Lanes left out because their runs could not be fetched
+}
+
+// TestOutcomes counts the executions of a test in a group of lanes
+type TestOutcomes struct {
+	Passes      int      `json:"passes"`
+	Failures    int      `json:"failures"`
+	FailureRate float64  `json:"failure_rate"` // Percentage of executions that failed
+	LastPass    string   `json:"last_pass,omitempty"`
+	LastFailure string   `json:"last_failure,omitempty"`
+	PassURLs    []string `json:"pass_urls,omitempty"`    // Newest passing runs
+	FailureURLs []string `json:"failure_urls,omitempty"` // Newest failing runs
+}
+
+// QuarantinedTest is how a quarantined test did in quarantine lanes and in regular lanes,
+// where it should no longer run
+type QuarantinedTest struct {
+	TestName          string       `json:"test_name"`
+	TestID            string       `json:"test_id,omitempty"`
+	SIG               string       `json:"sig,omitempty"`
+	DateQuarantined   string       `json:"date_quarantined,omitempty"`
+	JunitNames        []string     `json:"junit_names,omitempty"` // Full names of the matching testcases
+	QuarantineLanes   TestOutcomes `json:"quarantine_lanes"`
+	RegularLanes      TestOutcomes `json:"regular_lanes"`      // Executions since the quarantine date only
+	StableSince       string       `json:"stable_since"`       // Later of the quarantine date and the window start
+	StablePasses      int          `json:"stable_passes"`      // Passes since StableSince
+	StableFailures    int          `json:"stable_failures"`    // Failures since StableSince
+	Stable            bool         `json:"stable"`             // Passed at least MinExecutions times and never failed since StableSince
+	ContainedFailures float64      `json:"contained_failures"` // Fraction of failures that happened in quarantine lanes, 1 without failures
+}
+
+// QuarantineCandidate is a non-quarantined test that passed and failed on the same code
+type QuarantineCandidate struct {
+	TestName  string        `json:"test_name"`
+	Lanes     []string      `json:"lanes"`
+	Outcomes  TestOutcomes  `json:"outcomes"`
+	FlakeRate float64       `json:"flake_rate"` // Percentage of executions that failed
+	Flakes    TestFlakiness `json:"flakes"`     // Commit sets with both outcomes, as evidence
+}
+
+// AnalyzeQuarantine fetches the runs of the quarantine and regular lanes within
+// config.TimePeriod and reports, for every quarantined test, how often it ran and failed
+// in either, and the flaky tests of the regular lanes that are not quarantined yet.
+// Testcases are matched to quarantined tests with QuarantineEntry.Matches. Runs of regular
+// lanes from before a test's quarantine date are not counted for it, as the test was
+// expected to run there, and its stability is measured from the later of its quarantine
+// date and the window start. Lanes without runs in the window add nothing, lanes whose
+// runs cannot be fetched are listed in LaneErrors.
+func AnalyzeQuarantine(ctx context.Context, source Source, quarantineLanes, regularLanes []string, quarantined []QuarantineEntry, config QuarantineConfig) (*QuarantineReport, error) {
+	lanes := append(append([]string{}, quarantineLanes...), regularLanes...)
+	summaries, errs := FetchLaneSummaries(ctx, lanes, config.Parallel, func(ctx context.Context, lane string, parallel int) (*LaneSummary, error) {
+		runs, err := FetchJobHistoryWithTimePeriod(ctx, source, lane, config.TimePeriod, config.RunLimit)
+		if errors.Is(err, ErrNoJobHistory) {
+			// The lane did not run within the window
+			return &LaneSummary{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return AnalyzeLaneRuns(ctx, source, runs, parallel)
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := &QuarantineReport{
+		Window:          formatPeriod(config.TimePeriod),
+		QuarantineLanes: quarantineLanes,
+		RegularLanes:    regularLanes,
+		Quarantined:     []QuarantinedTest{},
+		FlakyCandidates: []QuarantineCandidate{},
+		LaneErrors:      []LaneError{},
+	}
+
+	windowStart := now().UTC().Add(-config.TimePeriod)
+	tests := make([]QuarantinedTest, len(quarantined))
+	quarantinedAt := make([]time.Time, len(quarantined)) // Zero when the date is unknown
+	stableFrom := make([]time.Time, len(quarantined))
+	for i, entry := range quarantined {
+		tests[i] = QuarantinedTest{
+			TestName:        entry.Name,
+			TestID:          entry.TestID,
+			SIG:             entry.SIG,
+			DateQuarantined: entry.DateQuarantined,
+		}
+		if date, err := time.Parse("2006-01-02", entry.DateQuarantined); err == nil {
+			quarantinedAt[i] = date
+		}
+		stableFrom[i] = windowStart
+		if quarantinedAt[i].After(windowStart) {
+			stableFrom[i] = quarantinedAt[i]
+		}
+		tests[i].StableSince = stableFrom[i].Format(time.RFC3339)
+	}
+	candidates := make(map[string]*QuarantineCandidate)
+	var regularRuns []JobRun
+
+	for i, lane := range lanes {
+		if errs[i] != nil {
+			report.LaneErrors = append(report.LaneErrors, LaneError{JobName: lane, Error: errs[i].Error()})
+			continue
+		}
+		inQuarantineLane := i < len(quarantineLanes)
+		runs := summaries[i].Runs
+		sortRunsNewestFirst(runs)
+		if !inQuarantineLane {
+			regularRuns = append(regularRuns, runs...)
+		}
+
+		for _, run := range runs {
+			started, hasStart := runStartTime(run)
+			record := func(testcases []Testcase, failed bool) {
+				for _, testcase := range testcases {
+					if i := quarantineEntryIndex(quarantined, testcase.Name); i >= 0 {
+						// Before its quarantine the test belonged in regular lanes
+						if !inQuarantineLane && hasStart && started.Before(quarantinedAt[i]) {
+							continue
+						}
+						test := &tests[i]
+						if !containsString(test.JunitNames, testcase.Name) {
+							test.JunitNames = append(test.JunitNames, testcase.Name)
+						}
+						outcomes := &test.RegularLanes
+						if inQuarantineLane {
+							outcomes = &test.QuarantineLanes
+						}
+						outcomes.record(run, failed)
+						if !hasStart || !started.Before(stableFrom[i]) {
+							if failed {
+								test.StableFailures++
+							} else {
+								test.StablePasses++
+							}
+						}
+						continue
+					}
+					if inQuarantineLane {
+						continue
+					}
+					candidate, ok := candidates[testcase.Name]
+					if !ok {
+						candidate = &QuarantineCandidate{TestName: testcase.Name}
+						candidates[testcase.Name] = candidate
+					}
+					if !containsString(candidate.Lanes, lane) {
+						candidate.Lanes = append(candidate.Lanes, lane)
+					}
+					candidate.Outcomes.record(run, failed)
+				}
+			}
+			record(run.Passed, false)
+			record(run.Failures, true)
+		}
+	}
+
+	for i := range tests {
+		test := &tests[i]
+		test.QuarantineLanes.finish()
+		test.RegularLanes.finish()
+		failures := test.QuarantineLanes.Failures + test.RegularLanes.Failures
+		test.ContainedFailures = 1
+		if failures > 0 {
+			test.ContainedFailures = float64(test.QuarantineLanes.Failures) / float64(failures)
+		}
+		test.Stable = test.StableFailures == 0 && test.StablePasses >= max(config.MinExecutions, 1)
+		sort.Strings(test.JunitNames)
+		report.Quarantined = append(report.Quarantined, *test)
+	}
+	sort.Slice(report.Quarantined, func(i, j int) bool {
+		return report.Quarantined[i].TestName < report.Quarantined[j].TestName
+	})
+
+	// Only tests that passed and failed on the same code are flaky; others may be broken
+	for _, flake := range DetectFlakes(regularRuns) {
+		candidate, ok := candidates[flake.TestName]
+		if !ok {
+			continue
+		}
+		candidate.Outcomes.finish()
+		candidate.FlakeRate = candidate.Outcomes.FailureRate
+		if candidate.FlakeRate < config.FlakeThreshold {
+			continue
+		}
+		candidate.Flakes = flake
+		sort.Strings(candidate.Lanes)
+		report.FlakyCandidates = append(report.FlakyCandidates, *candidate)
+	}
+	sort.Slice(report.FlakyCandidates, func(i, j int) bool {
+		a, b := report.FlakyCandidates[i], report.FlakyCandidates[j]
+		if a.FlakeRate != b.FlakeRate {
+			return a.FlakeRate > b.FlakeRate
+		}
+		return a.TestName < b.TestName
+	})
+
+	return report, nil
+}
+
+// runStartTime returns when a run started, and false when its timestamp cannot be parsed
+func runStartTime(run JobRun) (time.Time, bool) {
+	started, err := time.Parse(time.RFC3339, run.Timestamp)
+	return started, err == nil
+}
+
+// record counts an execution of the test in run, which is visited newest first
+func (o *TestOutcomes) record(run JobRun, failed bool) {
+	if failed {
+		o.Failures++
+		if o.LastFailure == "" {
+			o.LastFailure = run.Timestamp
+		}
+		if len(o.FailureURLs) < maxOutcomeURLs && !containsString(o.FailureURLs, run.URL) {
+			o.FailureURLs = append(o.FailureURLs, run.URL)
+		}
+		return
+	}
+	o.Passes++
+	if o.LastPass == "" {
+		o.LastPass = run.Timestamp
+	}
+	if len(o.PassURLs) < maxOutcomeURLs && !containsString(o.PassURLs, run.URL) {
+		o.PassURLs = append(o.PassURLs, run.URL)
+	}
+}
+
+// finish computes the failure rate once all executions are recorded
+func (o *TestOutcomes) finish() {
+	if executions := o.Passes + o.Failures; executions > 0 {
+		o.FailureRate = float64(o.Failures) / float64(executions) * 100
+	}
+}
@@ -0,0 +1,241 @@
+package healthcheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// testLabel matches bracketed Ginkgo labels such as [QUARANTINE], [Serial] or [test_id:1234]
+	testLabel = regexp.MustCompile(`\[[^\[\]]*\]`)
+	// testIDLabel matches the test_id label of a KubeVirt test
+	testIDLabel = regexp.MustCompile(`\[test_id:(\d+)\]`)
+	// sigLabel matches the SIG label of a KubeVirt test
+	sigLabel = regexp.MustCompile(`\[(sig-[a-z0-9-]+)\]`)
+	// reportDate matches the dates in the quarantined tests report
+	reportDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?(?:Z|[+-]\d{2}:?\d{2})?)?`)
+)
+
+// Columns of the table listing the tests in the quarantined tests report. Cells are read
+// by the exact header of their column; only the test column is required.
+const (
+	reportColumnTest   = "Test"
+	reportColumnTestID = "test_id"
+	reportColumnDate   = "Date quarantined"
+	reportColumnSIG    = "SIG"
+)
+
+// QuarantineEntry is a test listed in the quarantined tests report
+type QuarantineEntry struct {
+	Name            string `json:"name"`                       // Full test description without labels, as normalized by normalizeTestName
+	FullName        string `json:"full_name"`                  // Name as listed in the report, with labels
+	TestID          string `json:"test_id,omitempty"`          // Number of the test_id label
+	DateQuarantined string `json:"date_quarantined,omitempty"` // YYYY-MM-DD when the date could be parsed
+	SIG             string `json:"sig,omitempty"`              // Owning SIG, such as sig-compute
+	Location        string `json:"location,omitempty"`         // file:line of the quarantined node in a source checkout
+	Container       bool   `json:"container,omitempty"`        // Quarantined container of a source checkout, covering every test below it
+}
+
+// Matches reports whether a junit testcase name is the quarantined test. An entry with a
+// test_id only matches testcases carrying that test_id label; others match testcases
+// whose full description, container texts and It text, is the entry's name, or for a
+// container entry starts with it.
+func (e QuarantineEntry) Matches(testName string) bool {
+	return e.matches(testName, normalizeTestName(testName))
+}
+
+// matches is Matches for a testcase name already normalized by normalizeTestName
+func (e QuarantineEntry) matches(testName, normalizedName string) bool {
+	if e.TestID != "" {
+		return strings.Contains(testName, "[test_id:"+e.TestID+"]")
+	}
+	return e.Name != "" && (e.Name == normalizedName || e.coversName(normalizedName))
+}
+
+// coversName reports whether a container entry covers the test with a normalized name,
+// which starts with the container's description followed by the spec's
+func (e QuarantineEntry) coversName(name string) bool {
+	return e.Container && e.Name != "" && strings.HasPrefix(name, e.Name+" ")
+}
+
+// FindQuarantineEntry returns the entry matching a junit testcase name, or nil when the
+// test is not quarantined
+func FindQuarantineEntry(entries []QuarantineEntry, testName string) *QuarantineEntry {
+	if i := quarantineEntryIndex(entries, testName); i >= 0 {
+		return &entries[i]
+	}
+	return nil
+}
+
+// quarantineEntryIndex returns the index of the entry matching a junit testcase name, or -1
+func quarantineEntryIndex(entries []QuarantineEntry, testName string) int {
+	normalizedName := normalizeTestName(testName)
+	for i := range entries {
+		if entries[i].matches(testName, normalizedName) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseQuarantineReport parses the HTML page of the quarantined tests report. Tests are
+// read from the rows of the table whose header row has a reportColumnTest column; a page
+// without that table is an error rather than an empty list, so that a change of the
+// report's layout does not silently unquarantine every test.
+func ParseQuarantineReport(data []byte) ([]QuarantineEntry, error) {
+	var (
+		entries           []QuarantineEntry
+		columns           map[string]int // Index of each column of the current table by header
+		row               []string
+		headerRow, inCell bool
+		foundTable        bool
+		cell              strings.Builder
+	)
+
+	tokenizer := html.NewTokenizer(bytes.NewReader(data))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, fmt.Errorf("failed to parse quarantined tests report: %w", err)
+			}
+			break
+		}
+
+		token := tokenizer.Token()
+		switch tokenType {
+		case html.TextToken:
+			if inCell {
+				cell.WriteString(token.Data)
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			start := tokenType != html.EndTagToken
+			switch token.Data {
+			case "tr":
+				if start {
+					row, headerRow = nil, false
+				} else if headerRow {
+					columns = reportColumns(row)
+					foundTable = foundTable || columns != nil
+				} else if columns != nil {
+					if entry, ok := quarantineEntryFromRow(columns, row); ok {
+						entries = append(entries, entry)
+					}
+				}
+			case "th", "td":
+				if start {
+					inCell = true
+					cell.Reset()
+					headerRow = headerRow || token.Data == "th"
+				} else if inCell {
+					inCell = false
+					row = append(row, collapseSpaces(cell.String()))
+				}
+			case "table":
+				columns = nil
+			}
+		}
+	}
+
+	if !foundTable {
+		return nil, fmt.Errorf("failed to parse quarantined tests report: no table with a %q column", reportColumnTest)
+	}
+	return dedupeQuarantineEntries(entries), nil
+}
+
+// reportColumns indexes the known columns of a header row, or returns nil when the row
+// has no reportColumnTest column
+func reportColumns(headers []string) map[string]int {
+	columns := make(map[string]int)
+	for i, header := range headers {
+		switch header {
+		case reportColumnTest, reportColumnTestID, reportColumnDate, reportColumnSIG:
+			columns[header] = i
+		}
+	}
+	if _, ok := columns[reportColumnTest]; !ok {
+		return nil
+	}
+	return columns
+}
+
+// quarantineEntryFromRow builds an entry from the cells of a table row
+func quarantineEntryFromRow(columns map[string]int, cells []string) (QuarantineEntry, bool) {
+	value := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(cells) {
+			return cells[i]
+		}
+		return ""
+	}
+	return newQuarantineEntry(value(reportColumnTest), value(reportColumnTestID), value(reportColumnDate), value(reportColumnSIG))
+}
+
+// newQuarantineEntry builds an entry from the fields of the report, taking the test_id
+// and SIG from the labels of the name when they are not given
+func newQuarantineEntry(fullName, testID, date, sig string) (QuarantineEntry, bool) {
+	fullName = collapseSpaces(fullName)
+	entry := QuarantineEntry{
+		Name:            normalizeTestName(fullName),
+		FullName:        fullName,
+		TestID:          strings.TrimPrefix(strings.TrimSpace(testID), "test_id:"),
+		DateQuarantined: normalizeReportDate(date),
+		SIG:             strings.ToLower(strings.TrimSpace(sig)),
+	}
+	if entry.Name == "" {
+		return QuarantineEntry{}, false
+	}
+	if match := testIDLabel.FindStringSubmatch(fullName); match != nil && entry.TestID == "" {
+		entry.TestID = match[1]
+	}
+	if match := sigLabel.FindStringSubmatch(fullName); match != nil && entry.SIG == "" {
+		entry.SIG = match[1]
+	}
+	if entry.SIG != "" && !strings.HasPrefix(entry.SIG, "sig-") {
+		entry.SIG = "sig-" + entry.SIG
+	}
+	return entry, true
+}
+
+// normalizeTestName returns the description of a full test name without its labels, as
+// the container texts and It text joined by single spaces
+func normalizeTestName(fullName string) string {
+	return collapseSpaces(testLabel.ReplaceAllString(fullName, " "))
+}
+
+// normalizeReportDate formats dates of the report as YYYY-MM-DD, leaving others as they are
+func normalizeReportDate(value string) string {
+	value = strings.TrimSpace(value)
+	match := reportDate.FindString(value)
+	if match == "" {
+		return value
+	}
+	if t, err := time.Parse("2006-01-02", match[:10]); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return value
+}
+
+// dedupeQuarantineEntries drops entries listed more than once, keeping the first
+func dedupeQuarantineEntries(entries []QuarantineEntry) []QuarantineEntry {
+	seen := make(map[string]bool)
+	unique := []QuarantineEntry{}
+	for _, entry := range entries {
+		if seen[entry.FullName] {
+			continue
+		}
+		seen[entry.FullName] = true
+		unique = append(unique, entry)
+	}
+	return unique
+}
+
+// collapseSpaces trims a string and replaces every run of whitespace in it with one space
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
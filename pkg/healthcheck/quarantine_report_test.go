@@ -0,0 +1,83 @@
+package healthcheck
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuarantineReport(t *testing.T) {
+	data, err := os.ReadFile("testdata/quarantined-tests/index.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ParseQuarantineReport(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []QuarantineEntry{
+		{
+			Name:            "VM Live Migration should migrate a VMI with a hotplugged disk",
+			FullName:        "[sig-compute]VM Live Migration [QUARANTINE] should migrate a VMI with a hotplugged disk [test_id:8812]",
+			TestID:          "8812",
+			DateQuarantined: "2025-08-10",
+			SIG:             "sig-compute",
+		},
+		{
+			Name:            "VM Lifecycle should include VMI infos for a running VM",
+			FullName:        "[sig-compute]VM Lifecycle [QUARANTINE] should include VMI infos for a running VM",
+			DateQuarantined: "2025-07-02",
+			SIG:             "sig-compute",
+		},
+		{
+			Name:            "Services should be able to reach the VMI through a ClusterIP service",
+			FullName:        "[sig-network] Services [QUARANTINE] [Conformance] should be able to reach the VMI through a ClusterIP service [test_id:1547]",
+			TestID:          "1547",
+			DateQuarantined: "2025-06-23",
+			SIG:             "sig-network",
+		},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("ParseQuarantineReport() =\n%+v\nwant\n%+v", entries, want)
+	}
+}
+
+func TestParseQuarantineReportWithoutTestTable(t *testing.T) {
+	data := []byte("<table><tr><th>Name</th><th>Id</th></tr><tr><td>[QUARANTINE] should work</td><td>1</td></tr></table>")
+	if entries, err := ParseQuarantineReport(data); err == nil {
+		t.Errorf("ParseQuarantineReport() = %+v, want an error for a report without a %q column", entries, reportColumnTest)
+	}
+}
+
+func TestQuarantineEntryMatches(t *testing.T) {
+	entries, err := ParseQuarantineReport([]byte(`<table>
+		<tr><th>Test</th></tr>
+		<tr><td>[sig-compute]VM Lifecycle [QUARANTINE] should start [test_id:1525]</td></tr>
+		<tr><td>[sig-compute]VM Lifecycle [QUARANTINE] should stop</td></tr>
+	</table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	withID, withoutID := entries[0], entries[1]
+
+	tests := []struct {
+		entry    QuarantineEntry
+		testName string
+		want     bool
+	}{
+		{withID, "[sig-compute]VM Lifecycle should start [test_id:1525]", true},
+		// With a test_id only the label counts, not the description
+		{withID, "[sig-compute]VM Lifecycle should start [test_id:9999]", false},
+		{withID, "[sig-compute]VM Lifecycle should start", false},
+		{withoutID, "[sig-compute]VM Lifecycle [Serial]  should stop", true},
+		// The full description must match, not only the It text
+		{withoutID, "[sig-compute]VM Pool should stop", false},
+		{withoutID, "[sig-compute]VM Lifecycle should stop and start", false},
+	}
+	for _, tt := range tests {
+		if got := tt.entry.Matches(tt.testName); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.entry.FullName, tt.testName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ginkgoLeafNodes are the Ginkgo functions declaring a spec
+var ginkgoLeafNodes = map[string]bool{
+	"It": true, "FIt": true, "PIt": true, "XIt": true,
+	"Specify": true, "FSpecify": true, "PSpecify": true, "XSpecify": true,
+	"Entry": true, "FEntry": true, "PEntry": true, "XEntry": true,
+}
+
+// ginkgoContainerNodes are the Ginkgo functions grouping specs. Functions whose name ends
+// in Describe, such as KubeVirt's SIGDescribe wrappers, are containers too.
+var ginkgoContainerNodes = map[string]bool{
+	"Context": true, "FContext": true, "PContext": true, "XContext": true,
+	"When": true, "FWhen": true, "PWhen": true, "XWhen": true,
+	"DescribeTable": true, "FDescribeTable": true, "PDescribeTable": true, "XDescribeTable": true,
+	"DescribeTableSubtree": true,
+}
+
+// sourceSIGDirs are the SIGs owning the KubeVirt functional test directories
+var sourceSIGDirs = map[string]string{
+	"compute":     "sig-compute",
+	"migration":   "sig-compute",
+	"network":     "sig-network",
+	"storage":     "sig-storage",
+	"operator":    "sig-operator",
+	"monitoring":  "sig-monitoring",
+	"performance": "sig-performance",
+}
+
+// QuarantineDiff compares the quarantined tests of a source checkout with the published report
+type QuarantineDiff struct {
+	Pending []QuarantineEntry `json:"pending"` // Quarantined in the source but not listed in the report yet
+	Lifted  []QuarantineEntry `json:"lifted"`  // Listed in the report but no longer quarantined in the source
+}
+
+// LoadQuarantinedTests returns the quarantined tests of the KubeVirt checkout at sourceDir,
+// or those of the published report at url when sourceDir is empty
+func LoadQuarantinedTests(ctx context.Context, url, sourceDir string) ([]QuarantineEntry, error) {
+	if sourceDir != "" {
+		return ScanQuarantineSource(sourceDir)
+	}
+	return FetchQuarantinedTests(ctx, url)
+}
+
+// ScanQuarantineSource finds the quarantined specs in the Go sources of a KubeVirt
+// checkout. A node is quarantined when its text carries a [QUARANTINE] label or it is
+// decorated with decorators.Quarantine or Label("QUARANTINE"); every spec of a quarantined
+// container is listed. A quarantined container whose specs cannot be listed, because they
+// are declared in helper functions or have computed texts, is listed as a container entry
+// covering all tests below it. Vendored code is skipped.
+func ScanQuarantineSource(root string) ([]QuarantineEntry, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quarantine source: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("quarantine source %s is not a directory", root)
+	}
+
+	var entries []QuarantineEntry
+	fset := token.NewFileSet()
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "_out" || name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		// Most files cannot quarantine anything
+		if !bytes.Contains(bytes.ToUpper(data), []byte("QUARANTINE")) {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, data, parser.SkipObjectResolution)
+		if err != nil {
+			// Files that do not compile, such as templates, hold no specs to run
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		scanner := &quarantineScanner{fset: fset, path: filepath.ToSlash(rel), sig: sourceDirSIG(rel)}
+		scanner.inspect(file, nil, "", false)
+		entries = append(entries, scanner.entries...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan quarantine source: %w", err)
+	}
+	return dedupeQuarantineEntries(entries), nil
+}
+
+// DiffQuarantine compares the quarantined tests found in the source with the published report
+func DiffQuarantine(source, published []QuarantineEntry) *QuarantineDiff {
+	diff := &QuarantineDiff{Pending: []QuarantineEntry{}, Lifted: []QuarantineEntry{}}
+	for _, entry := range source {
+		if !containsQuarantineEntry(published, entry) {
+			diff.Pending = append(diff.Pending, entry)
+		}
+	}
+	for _, entry := range published {
+		if !containsQuarantineEntry(source, entry) {
+			diff.Lifted = append(diff.Lifted, entry)
+		}
+	}
+	return diff
+}
+
+// containsQuarantineEntry reports whether entries list the same test as entry, by test_id
+// when both have one and by full description, without labels, otherwise. A container
+// entry lists the tests whose description starts with the container's.
+func containsQuarantineEntry(entries []QuarantineEntry, entry QuarantineEntry) bool {
+	for _, other := range entries {
+		if entry.TestID != "" && other.TestID != "" {
+			if entry.TestID == other.TestID {
+				return true
+			}
+			continue
+		}
+		if entry.Name == other.Name || entry.coversName(other.Name) || other.coversName(entry.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// quarantineScanner collects the quarantined Ginkgo nodes of one file
+type quarantineScanner struct {
+	fset    *token.FileSet
+	path    string // Path of the file relative to the checkout
+	sig     string // SIG owning the file's directory, if known
+	entries []QuarantineEntry
+}
+
+// inspect walks node, tracking the texts and SIG of the enclosing Ginkgo containers and
+// whether one of them is quarantined
+func (s *quarantineScanner) inspect(node ast.Node, texts []string, sig string, quarantined bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name := calleeName(call.Fun)
+		leaf := ginkgoLeafNodes[name]
+		if !leaf && !ginkgoContainerNodes[name] && !strings.HasSuffix(name, "Describe") {
+			return true
+		}
+
+		text, nodeSIG, nodeQuarantined := "", sig, quarantined
+		for i, arg := range call.Args {
+			if i == 0 {
+				if literal, ok := stringLiteral(arg); ok {
+					text = literal
+					continue
+				}
+			}
+			nodeQuarantined = nodeQuarantined || isQuarantineDecorator(arg)
+			if decoratorSIG := sigDecorator(arg); decoratorSIG != "" {
+				nodeSIG = decoratorSIG
+			}
+		}
+		nodeQuarantined = nodeQuarantined || strings.Contains(strings.ToUpper(text), "[QUARANTINE]")
+
+		nodeTexts := texts
+		if text != "" {
+			nodeTexts = append(append([]string{}, texts...), text)
+		}
+		if match := sigLabel.FindStringSubmatch(text); match != nil {
+			nodeSIG = match[1]
+		}
+
+		if leaf {
+			if nodeQuarantined {
+				// Without a literal text the spec's description is unknown, the entry
+				// covers the specs of the enclosing containers
+				s.record(call, nodeTexts, nodeSIG, text == "")
+			}
+			return false
+		}
+		recorded := len(s.entries)
+		for _, arg := range call.Args {
+			s.inspect(arg, nodeTexts, nodeSIG, nodeQuarantined)
+		}
+		if nodeQuarantined && !quarantined && len(s.entries) == recorded {
+			s.record(call, nodeTexts, nodeSIG, true)
+		}
+		return false
+	})
+}
+
+// record adds the quarantined node with the given texts as an entry, a container entry
+// when it covers every spec below the texts
+func (s *quarantineScanner) record(call *ast.CallExpr, texts []string, sig string, container bool) {
+	if sig == "" {
+		sig = s.sig
+	}
+	entry, ok := newQuarantineEntry(strings.Join(texts, " "), "", "", sig)
+	if !ok {
+		return
+	}
+	entry.Container = container
+	position := s.fset.Position(call.Pos())
+	entry.Location = fmt.Sprintf("%s:%d", s.path, position.Line)
+	s.entries = append(s.entries, entry)
+}
+
+// calleeName returns the name of a called function, without its package
+func calleeName(fun ast.Expr) string {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name
+	case *ast.SelectorExpr:
+		return f.Sel.Name
+	}
+	return ""
+}
+
+// stringLiteral evaluates string literals and concatenations of them
+func stringLiteral(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringLiteral(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringLiteral(e.Y)
+		return left + right, ok
+	case *ast.ParenExpr:
+		return stringLiteral(e.X)
+	}
+	return "", false
+}
+
+// isQuarantineDecorator reports whether a Ginkgo decorator is decorators.Quarantine or a
+// Label call with the QUARANTINE label
+func isQuarantineDecorator(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		return calleeName(e) == "Quarantine"
+	case *ast.CallExpr:
+		if calleeName(e.Fun) != "Label" {
+			return false
+		}
+		for _, arg := range e.Args {
+			if label, ok := stringLiteral(arg); ok && strings.EqualFold(label, "QUARANTINE") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sigDecorator returns the SIG of a decorator such as decorators.SigCompute or
+// Label("sig-compute"), or "" for other decorators
+func sigDecorator(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		name := calleeName(e)
+		if strings.HasPrefix(name, "Sig") && len(name) > 3 && unicode.IsUpper(rune(name[3])) {
+			return kebabCase(name)
+		}
+	case *ast.CallExpr:
+		if calleeName(e.Fun) != "Label" {
+			return ""
+		}
+		for _, arg := range e.Args {
+			if label, ok := stringLiteral(arg); ok && strings.HasPrefix(label, "sig-") {
+				return label
+			}
+		}
+	}
+	return ""
+}
+
+// kebabCase turns an identifier such as SigComputeMigrations into sig-compute-migrations
+func kebabCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// sourceDirSIG returns the SIG owning a file below tests/ of a KubeVirt checkout, or ""
+func sourceDirSIG(rel string) string {
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "tests" {
+			return sourceSIGDirs[parts[i+1]]
+		}
+	}
+	return ""
+}
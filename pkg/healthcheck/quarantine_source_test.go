@@ -0,0 +1,83 @@
+package healthcheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffQuarantine(t *testing.T) {
+	entry := func(fullName string) QuarantineEntry {
+		entry, ok := newQuarantineEntry(fullName, "", "", "")
+		if !ok {
+			t.Fatalf("no entry for %q", fullName)
+		}
+		return entry
+	}
+	source := []QuarantineEntry{
+		entry("[sig-compute]VM Lifecycle [QUARANTINE] should start [test_id:1525]"),
+		entry("[sig-storage]Hotplug [QUARANTINE] should hotplug a disk"),
+		entry("[sig-network]Services [QUARANTINE] should be reachable"),
+	}
+	published := []QuarantineEntry{
+		// Same test_id, renamed since the report was published
+		entry("[sig-compute]VM Lifecycle [QUARANTINE] should start a VM [test_id:1525]"),
+		// Same It text in another container is another test
+		entry("[sig-storage]Hotplug with a filesystem [QUARANTINE] should hotplug a disk"),
+		entry("[sig-network] Services [Conformance] [QUARANTINE] should be reachable"),
+	}
+	diff := DiffQuarantine(source, published)
+
+	if len(diff.Pending) != 1 || diff.Pending[0].FullName != source[1].FullName {
+		t.Errorf("Pending = %+v, want only %q", diff.Pending, source[1].FullName)
+	}
+	if len(diff.Lifted) != 1 || diff.Lifted[0].FullName != published[1].FullName {
+		t.Errorf("Lifted = %+v, want only %q", diff.Lifted, published[1].FullName)
+	}
+}
+
+func TestScanQuarantineSource(t *testing.T) {
+	entries, err := ScanQuarantineSource("testdata/quarantine-source")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []QuarantineEntry{
+		{Name: "VM Lifecycle should start", FullName: "[sig-compute]VM Lifecycle [test_id:1525]should start", TestID: "1525", SIG: "sig-compute", Location: "tests/compute/lifecycle.go:4"},
+		{Name: "VM Lifecycle with a stopped VM should stop", FullName: "[sig-compute]VM Lifecycle with a stopped VM should stop", SIG: "sig-compute", Location: "tests/compute/lifecycle.go:7"},
+		{Name: "Services", FullName: "[sig-network]Services", SIG: "sig-network", Location: "tests/network/services.go:4", Container: true},
+		{Name: "Hotplug should hotplug a disk", FullName: "Hotplug [QUARANTINE]should hotplug a disk", SIG: "sig-storage", Location: "tests/storage/hotplug.go:4"},
+		{Name: "Hotplug with bus virtio", FullName: "Hotplug with bus virtio", SIG: "sig-storage", Location: "tests/storage/hotplug.go:9"},
+		{Name: "Hotplug with bus scsi", FullName: "Hotplug with bus [test_id:3000]scsi", TestID: "3000", SIG: "sig-storage", Location: "tests/storage/hotplug.go:10"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("ScanQuarantineSource() =\n%+v\nwant\n%+v", entries, want)
+	}
+
+	tests := []struct {
+		testName string
+		want     bool
+	}{
+		{"[sig-compute]VM Lifecycle should start [test_id:1525]", true},
+		{"[sig-compute]VM Lifecycle with a stopped VM should stop", true},
+		{"[sig-compute]VM Pool should scale", false},
+		// Specs of the container declared by helpers
+		{"[sig-network]Services should reach the VMI [test_id:1547]", true},
+		{"[sig-network]ServicesWithoutSpace should reach the VMI", false},
+		{"[sig-storage]Hotplug should unplug a disk", false},
+		{"[sig-storage]Hotplug with bus virtio", true},
+	}
+	for _, tt := range tests {
+		if got := FindQuarantineEntry(entries, tt.testName) != nil; got != tt.want {
+			t.Errorf("quarantined(%q) = %v, want %v", tt.testName, got, tt.want)
+		}
+	}
+
+	// The report lists the specs of the container
+	published, ok := newQuarantineEntry("[sig-network]Services [QUARANTINE] should reach the VMI", "", "", "")
+	if !ok {
+		t.Fatal("no entry for the published spec")
+	}
+	if diff := DiffQuarantine(entries, []QuarantineEntry{published}); len(diff.Lifted) != 0 || containsQuarantineEntry(diff.Pending, entries[2]) {
+		t.Errorf("DiffQuarantine() = %+v, want the container to list the published spec", diff)
+	}
+}
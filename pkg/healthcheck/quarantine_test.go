@@ -0,0 +1,60 @@
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAnalyzeQuarantineFromQuarantineDate analyzes testdata/quarantine, where a test failed
+// in a regular lane on 2025-08-05, was quarantined on 2025-08-10 and then passed three
+// times in the quarantine lane
+func TestAnalyzeQuarantineFromQuarantineDate(t *testing.T) {
+	withNow(t, time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC))
+
+	entry := QuarantineEntry{
+		Name:     "VM Live Migration should migrate a VMI with a hotplugged disk",
+		FullName: "[sig-compute]VM Live Migration [QUARANTINE] should migrate a VMI with a hotplugged disk [test_id:8812]",
+		TestID:   "8812",
+	}
+	config := QuarantineConfig{TimePeriod: 14 * 24 * time.Hour, RunLimit: 100, Parallel: 2, MinExecutions: 3, FlakeThreshold: 5}
+	analyze := func(dateQuarantined string) QuarantinedTest {
+		t.Helper()
+		entry := entry
+		entry.DateQuarantined = dateQuarantined
+		report, err := AnalyzeQuarantine(context.Background(), NewLocalSource("testdata/quarantine"),
+			[]string{"pull-kubevirt-e2e-k8s-1.33-sig-compute-quarantined"},
+			// The idle lane has no runs, which is not an error
+			[]string{"pull-kubevirt-e2e-k8s-1.33-sig-compute", "pull-kubevirt-e2e-k8s-1.33-sig-compute-idle"},
+			[]QuarantineEntry{entry}, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(report.LaneErrors) != 0 {
+			t.Fatalf("LaneErrors = %v", report.LaneErrors)
+		}
+		return report.Quarantined[0]
+	}
+
+	test := analyze("2025-08-10")
+	if test.RegularLanes.Failures != 0 {
+		t.Errorf("regular lane failures = %d, want the failure before the quarantine ignored", test.RegularLanes.Failures)
+	}
+	if test.QuarantineLanes.Passes != 3 {
+		t.Errorf("quarantine lane passes = %d, want 3", test.QuarantineLanes.Passes)
+	}
+	if test.StableSince != "2025-08-10T00:00:00Z" || test.StablePasses != 3 || !test.Stable {
+		t.Errorf("stable since %s with %d passes, stable %v; want since 2025-08-10 with 3 passes, stable",
+			test.StableSince, test.StablePasses, test.Stable)
+	}
+
+	// Without a quarantine date the whole window counts
+	test = analyze("")
+	if test.RegularLanes.Failures != 1 {
+		t.Errorf("regular lane failures = %d, want 1", test.RegularLanes.Failures)
+	}
+	if test.StableSince != "2025-07-31T12:00:00Z" || test.StableFailures != 1 || test.Stable {
+		t.Errorf("stable since %s with %d failures, stable %v; want since the window start with 1 failure, not stable",
+			test.StableSince, test.StableFailures, test.Stable)
+	}
+}
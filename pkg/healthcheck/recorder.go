@@ -0,0 +1,203 @@
+package healthcheck
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// recordingInfoFile holds the metadata of a whole recording, next to the responses
+const recordingInfoFile = "recording.json"
+
+// recordingInfo is the metadata of a recording
+type recordingInfo struct {
+	CapturedAt time.Time `json:"captured_at"` // When recording started, the "now" of time periods
+}
+
+// recordedResponse is the metadata of a recorded HTTP response. The body is stored
+// next to it, unmodified, so fixtures stay readable and byte-exact.
+type recordedResponse struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+}
+
+// recordingTransport saves every response received through it to a directory
+type recordingTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// NewRecordingTransport returns a transport that sends requests through next and saves
+// every response in dir, in the format served by NewReplayTransport
+func NewRecordingTransport(dir string, next http.RoundTripper) (http.RoundTripper, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create record directory: %w", err)
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	info, err := json.MarshalIndent(recordingInfo{CapturedAt: now().UTC()}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal recording info: %w", err)
+	}
+	if err := writeFileAtomic(filepath.Join(dir, recordingInfoFile), info); err != nil {
+		return nil, fmt.Errorf("failed to save recording info: %w", err)
+	}
+	return &recordingTransport{dir: dir, next: next}, nil
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	recorded := recordedResponse{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     http.Header{"Content-Type": resp.Header.Values("Content-Type")},
+	}
+	if err := t.save(recorded, body); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes the metadata and body of a response, replacing an earlier recording
+func (t *recordingTransport) save(recorded recordedResponse, body []byte) error {
+	metadata, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded response: %w", err)
+	}
+
+	base := recordingPath(t.dir, recorded.Method, recorded.URL)
+	if err := writeFileAtomic(base+".body", body); err != nil {
+		return fmt.Errorf("failed to record %s: %w", recorded.URL, err)
+	}
+	if err := writeFileAtomic(base+".json", metadata); err != nil {
+		return fmt.Errorf("failed to record %s: %w", recorded.URL, err)
+	}
+	return nil
+}
+
+// replayTransport serves responses saved by a recordingTransport without network access
+type replayTransport struct {
+	dir string
+}
+
+// NewReplayTransport returns a transport that serves the responses recorded in dir.
+// Requests that were not recorded fail.
+func NewReplayTransport(dir string) (http.RoundTripper, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("replay path %s is not a directory", dir)
+	}
+	return &replayTransport{dir: dir}, nil
+}
+
+// RecordingTime returns when the recording in dir was captured, or the zero time for
+// recordings made before the capture time was saved
+func RecordingTime(dir string) (time.Time, error) {
+	data, err := os.ReadFile(filepath.Join(dir, recordingInfoFile))
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read recording info: %w", err)
+	}
+
+	var info recordingInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse recording info: %w", err)
+	}
+	return info.CapturedAt, nil
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	base := recordingPath(t.dir, req.Method, req.URL.String())
+	metadata, err := os.ReadFile(base + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL)
+	}
+
+	var recorded recordedResponse
+	if err := json.Unmarshal(metadata, &recorded); err != nil {
+		return nil, fmt.Errorf("failed to parse recorded response for %s: %w", req.URL, err)
+	}
+
+	body, err := os.ReadFile(base + ".body")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded body for %s: %w", req.URL, err)
+	}
+
+	header := recorded.Header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// recordingPath returns the path, without extension, of the recording of a request
+func recordingPath(dir, method, url string) string {
+	sum := sha256.Sum256([]byte(method + " " + url))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16]))
+}
+
+// writeFileAtomic writes data to path through a temporary file so readers never see partial content
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
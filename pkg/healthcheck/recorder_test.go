@@ -0,0 +1,167 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// withNow measures time periods from t until the test ends
+func withNow(t *testing.T, at time.Time) {
+	t.Helper()
+	saved := now
+	SetNow(at)
+	t.Cleanup(func() { now = saved })
+}
+
+// withTransport sends the requests of the shared HTTP client through transport until the test ends
+func withTransport(t *testing.T, transport http.RoundTripper) {
+	t.Helper()
+	config := DefaultHTTPClientConfig()
+	config.Transport = transport
+	config.RequestsPerSecond = 0
+	ConfigureHTTPClient(config)
+	t.Cleanup(func() { ConfigureHTTPClient(DefaultHTTPClientConfig()) })
+}
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prowjob.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"status":{"state":"success"}}`)
+	}))
+	defer server.Close()
+
+	capturedAt := time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC)
+	withNow(t, capturedAt)
+
+	dir := t.TempDir()
+	recorder, err := NewRecordingTransport(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorded := map[string]int{
+		server.URL + "/prowjob.json":               http.StatusOK,
+		server.URL + "/artifacts/junit.xml?page=2": http.StatusNotFound,
+	}
+	bodies := make(map[string]string)
+	for url := range recorded {
+		bodies[url] = get(t, recorder, url, recorded[url])
+	}
+	server.Close()
+
+	replayer, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for url, status := range recorded {
+		if body := get(t, replayer, url, status); body != bodies[url] {
+			t.Errorf("replayed body of %s = %q, recorded %q", url, body, bodies[url])
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/finished.json", nil)
+	if _, err := replayer.RoundTrip(req); err == nil {
+		t.Error("replaying an unrecorded request succeeded")
+	}
+
+	got, err := RecordingTime(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(capturedAt) {
+		t.Errorf("RecordingTime() = %v, want %v", got, capturedAt)
+	}
+}
+
+func TestRecordingTimeWithoutInfo(t *testing.T) {
+	got, err := RecordingTime(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsZero() {
+		t.Errorf("RecordingTime() = %v, want the zero time", got)
+	}
+}
+
+// get fetches url through transport, checks the status code and returns the body
+func get(t *testing.T, transport http.RoundTripper, url string, wantStatus int) string {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != wantStatus {
+		t.Errorf("GET %s: status %d, want %d", url, resp.StatusCode, wantStatus)
+	}
+	return string(body)
+}
+
+// TestReplayLane replays `healthcheck lane pull-kubevirt-e2e-k8s-1.33-sig-compute --since 2d`.
+// The recorded job history lists three runs, the oldest started more than two days before
+// the capture, so replaying must select the two newer runs whatever the current time is.
+func TestReplayLane(t *testing.T) {
+	const dir = "testdata/replay/lane-since-2d"
+	capturedAt, err := RecordingTime(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if capturedAt.IsZero() {
+		t.Fatal("fixture has no capture time")
+	}
+	withNow(t, capturedAt)
+
+	replayer, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withTransport(t, replayer)
+
+	ctx := context.Background()
+	source := NewProwSource(DefaultProwConfig(), nil)
+	runs, err := FetchJobHistoryWithTimePeriod(ctx, source, "pull-kubevirt-e2e-k8s-1.33-sig-compute", 48*time.Hour, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := AnalyzeLaneRuns(ctx, source, runs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if summary.TotalRuns != 2 || summary.SuccessfulRuns != 1 || summary.FailedRuns != 1 {
+		t.Errorf("runs: total %d, successful %d, failed %d; want 2, 1, 1",
+			summary.TotalRuns, summary.SuccessfulRuns, summary.FailedRuns)
+	}
+	wantIDs := []string{"1955940000000000001", "1955610000000000002"}
+	for i, run := range summary.Runs {
+		if i >= len(wantIDs) || run.ID != wantIDs[i] {
+			t.Errorf("run %d is %s, want %v", i, run.ID, wantIDs)
+		}
+		if run.JobType != "presubmit" {
+			t.Errorf("run %s has job type %q, want presubmit", run.ID, run.JobType)
+		}
+	}
+
+	failing := "[sig-compute]VM Lifecycle [rfe_id:1177][crit:medium] should restart a running VM [test_id:1526]"
+	if len(summary.TestFailures) != 1 || summary.TestFailures[failing] != 1 {
+		t.Errorf("TestFailures = %v, want %q failing once", summary.TestFailures, failing)
+	}
+	if stats := summary.TestStats[failing]; stats.Executions != 2 {
+		t.Errorf("%q ran %d times, want 2", failing, stats.Executions)
+	}
+}
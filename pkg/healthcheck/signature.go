@@ -0,0 +1,194 @@
+package healthcheck
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSignatureLength caps signatures so long stack dumps do not become unique keys
+const maxSignatureLength = 300
+
+// signatureReplacements normalize the parts of a failure message that differ between
+// occurrences of the same problem. They are applied in order, so specific patterns
+// like UUIDs and timestamps run before the generic number replacement.
+var signatureReplacements = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// ANSI color codes from Ginkgo output
+	{regexp.MustCompile(`\x1b\[[0-9;]*m`), ""},
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`), "<uuid>"},
+	// MAC addresses, before the time and IPv6 patterns that would consume their groups
+	{regexp.MustCompile(`(?i)\b([0-9a-f]{2}:){5}[0-9a-f]{2}\b`), "<mac>"},
+	// RFC3339 and similar date-times such as Go's time.Time strings, then syslog style timestamps
+	{regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z| ?[+-]\d{2}:?\d{2})?( [A-Z]{3,4})?`), "<time>"},
+	{regexp.MustCompile(`\b(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) +\d{1,2} \d{2}:\d{2}:\d{2}(\.\d+)?`), "<time>"},
+	{regexp.MustCompile(`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b`), "<time>"},
+	// IPv4 and IPv6 addresses with optional ports, and memory addresses
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<ip>"},
+	{regexp.MustCompile(`(?i)\b([0-9a-f]{1,4}:){2,7}[0-9a-f]{1,4}\b`), "<ip>"},
+	{regexp.MustCompile(`\b0x[0-9a-fA-F]+\b`), "<addr>"},
+	// Source locations drift with every change to the file
+	{regexp.MustCompile(`(\.go|\.sh|\.py):\d+`), "$1:<line>"},
+	// Durations such as "after 300.001s"
+	{regexp.MustCompile(`\b\d+(\.\d+)?(ns|µs|us|ms|s|m|h)\b`), "<duration>"},
+	// Long hexadecimal IDs such as container IDs and commit SHAs
+	{regexp.MustCompile(`(?i)\b[0-9a-f]{12,64}\b`), "<id>"},
+	// Remaining numbers with four or more digits: ports, resource versions, PIDs
+	{regexp.MustCompile(`\b\d{4,}\b`), "<n>"},
+	{regexp.MustCompile(`\s+`), " "},
+}
+
+// hyphenatedName matches names that may carry the random suffixes Kubernetes and the
+// tests append to generated objects, such as pod names of deployments or test VMIs
+var hyphenatedName = regexp.MustCompile(`\b[a-z][a-z0-9]*(?:-[a-z0-9]+)+\b`)
+
+// FailureSignature normalizes the message of a failed testcase into a signature shared by
+// all failures with the same cause: UUIDs, generated pod names, timestamps, addresses and
+// line numbers are replaced with placeholders. Testcases without a failure message, such
+// as infrastructure placeholders, use their name.
+func FailureSignature(testcase Testcase) string {
+	if testcase.Failure == nil {
+		return testcase.Name
+	}
+
+	message := testcase.Failure.Message
+	if strings.TrimSpace(message) == "" {
+		message = testcase.Failure.Value
+	}
+	if strings.TrimSpace(message) == "" {
+		return testcase.Name
+	}
+
+	signature := message
+	for _, r := range signatureReplacements {
+		signature = r.pattern.ReplaceAllString(signature, r.replacement)
+	}
+	signature = hyphenatedName.ReplaceAllStringFunc(signature, normalizeGeneratedName)
+	signature = strings.TrimSpace(signature)
+
+	if len(signature) > maxSignatureLength {
+		// Cut on a rune boundary so the signature stays valid UTF-8
+		cut := maxSignatureLength
+		for cut > 0 && !utf8.RuneStart(signature[cut]) {
+			cut--
+		}
+		signature = signature[:cut]
+	}
+	return signature
+}
+
+// generatedSuffixAlphabet holds the characters of the random suffixes Kubernetes generates,
+// without vowels and easily confused digits
+const generatedSuffixAlphabet = "bcdfghjklmnpqrstvwxz2456789"
+
+// normalizeGeneratedName replaces the random suffixes of a generated object name. Suffix
+// segments containing a digit are considered random, as are five character segments
+// drawn from the alphabet Kubernetes generates them from, so plain words survive.
+func normalizeGeneratedName(name string) string {
+	parts := strings.Split(name, "-")
+	changed := false
+	for i := 1; i < len(parts); i++ {
+		if isGeneratedSuffix(parts[i]) {
+			parts[i] = "<rand>"
+			changed = true
+		}
+	}
+	if !changed {
+		return name
+	}
+	return strings.Join(parts, "-")
+}
+
+// isGeneratedSuffix reports whether a segment of a hyphenated name looks random
+func isGeneratedSuffix(segment string) bool {
+	if len(segment) >= 5 && len(segment) <= 10 && strings.ContainsAny(segment, "0123456789") &&
+		strings.ContainsAny(segment, "abcdefghijklmnopqrstuvwxyz") {
+		return true
+	}
+	if len(segment) != 5 {
+		return false
+	}
+	for _, r := range segment {
+		if !strings.ContainsRune(generatedSuffixAlphabet, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// SignatureCluster is a group of failures sharing a signature, across tests and lanes
+type SignatureCluster struct {
+	Signature     string         `json:"signature"`
+	Count         int            `json:"failure_count"`
+	Tests         []string       `json:"tests"`       // Most frequently failing first
+	TestCounts    map[string]int `json:"test_counts"` // Failures per test
+	Lanes         []string       `json:"lanes,omitempty"`
+	SampleMessage string         `json:"sample_message,omitempty"` // Original message of one failure
+	URLs          []string       `json:"urls"`
+}
+
+// ClusterFailures groups failed testcases by their failure signature, largest cluster first
+func ClusterFailures(testcases []Testcase) []SignatureCluster {
+	clusters := make(map[string]*SignatureCluster)
+	var order []string
+
+	for _, testcase := range testcases {
+		signature := FailureSignature(testcase)
+		cluster, ok := clusters[signature]
+		if !ok {
+			cluster = &SignatureCluster{Signature: signature, TestCounts: make(map[string]int)}
+			if testcase.Failure != nil {
+				cluster.SampleMessage = testcase.Failure.Message
+			}
+			clusters[signature] = cluster
+			order = append(order, signature)
+		}
+
+		cluster.Count++
+		cluster.TestCounts[testcase.Name]++
+		if testcase.URL != "" && !containsString(cluster.URLs, testcase.URL) {
+			cluster.URLs = append(cluster.URLs, testcase.URL)
+		}
+		if lane := jobNameFromRunURL(testcase.URL); lane != "" && !containsString(cluster.Lanes, lane) {
+			cluster.Lanes = append(cluster.Lanes, lane)
+		}
+	}
+
+	result := make([]SignatureCluster, 0, len(order))
+	for _, signature := range order {
+		cluster := clusters[signature]
+		for test := range cluster.TestCounts {
+			cluster.Tests = append(cluster.Tests, test)
+		}
+		sort.Slice(cluster.Tests, func(i, j int) bool {
+			a, b := cluster.Tests[i], cluster.Tests[j]
+			if cluster.TestCounts[a] != cluster.TestCounts[b] {
+				return cluster.TestCounts[a] > cluster.TestCounts[b]
+			}
+			return a < b
+		})
+		sort.Strings(cluster.Lanes)
+		result = append(result, *cluster)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return len(result[i].Tests) > len(result[j].Tests)
+	})
+	return result
+}
+
+// jobNameFromRunURL returns the job name of a run URL, which always ends in <job>/<build-id>
+func jobNameFromRunURL(runURL string) string {
+	trimmed := strings.TrimSuffix(runURL, "/")
+	if !strings.Contains(trimmed, "/") {
+		return ""
+	}
+	return path.Base(path.Dir(trimmed))
+}
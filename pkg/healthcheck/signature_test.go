@@ -0,0 +1,89 @@
+package healthcheck
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFailureSignature(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string // Occurrences of the same failure
+		want     string
+	}{
+		{
+			name: "UUIDs",
+			messages: []string{
+				"VMI 1c4e8a0b-3f2d-4d6e-9a7b-0c1d2e3f4a5b was not found",
+				"VMI F47AC10B-58CC-4372-A567-0E02B2C3D479 was not found",
+			},
+			want: "VMI <uuid> was not found",
+		},
+		{
+			name: "pod names",
+			messages: []string{
+				"pod virt-launcher-testvmi-kzxpq is not running",
+				"pod virt-launcher-testvmi-7b2fq is not running",
+			},
+			want: "pod virt-launcher-testvmi-<rand> is not running",
+		},
+		{
+			name: "pod names of deployments",
+			messages: []string{
+				"virt-handler-7c9f8d6b54-hwxzd failed to start",
+				"virt-handler-5f4d8c7b9f-t2kq6 failed to start",
+			},
+			want: "virt-handler-<rand>-<rand> failed to start",
+		},
+		{
+			name: "plain words",
+			messages: []string{
+				"deployment virt-operator has no ready replicas",
+			},
+			want: "deployment virt-operator has no ready replicas",
+		},
+		{
+			name: "timestamps",
+			messages: []string{
+				"timed out at 2025-08-14T12:03:45.123Z waiting for migration",
+				"timed out at 2025-08-15 08:00:01 +0000 UTC waiting for migration",
+			},
+			want: "timed out at <time> waiting for migration",
+		},
+		{
+			name: "line numbers",
+			messages: []string{
+				"Expected success at tests/migration/migration.go:1523",
+				"Expected success at tests/migration/migration.go:1547",
+			},
+			want: "Expected success at tests/migration/migration.go:<line>",
+		},
+	}
+	for _, tt := range tests {
+		for _, message := range tt.messages {
+			if got := FailureSignature(Testcase{Failure: &Failure{Message: message}}); got != tt.want {
+				t.Errorf("%s: FailureSignature(%q) = %q, want %q", tt.name, message, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestFailureSignatureMACAddress(t *testing.T) {
+	testcase := Testcase{Failure: &Failure{Message: "interface 52:54:00:ab:cd:ef has no address"}}
+	if got, want := FailureSignature(testcase), "interface <mac> has no address"; got != want {
+		t.Errorf("FailureSignature() = %q, want %q", got, want)
+	}
+}
+
+func TestFailureSignatureTruncatesOnRuneBoundary(t *testing.T) {
+	// Two-byte runes put the byte limit in the middle of one
+	message := "x" + strings.Repeat("é", maxSignatureLength)
+	got := FailureSignature(Testcase{Failure: &Failure{Message: message}})
+	if !utf8.ValidString(got) {
+		t.Errorf("FailureSignature() is not valid UTF-8: %q", got[len(got)-4:])
+	}
+	if len(got) > maxSignatureLength {
+		t.Errorf("FailureSignature() is %d bytes, want at most %d", len(got), maxSignatureLength)
+	}
+}
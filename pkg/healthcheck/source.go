@@ -0,0 +1,34 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoJobHistory is wrapped by the errors of ListRuns for jobs without runs, or without
+// runs within the requested period, as opposed to runs that could not be listed
+var ErrNoJobHistory = errors.New("no job history found")
+
+// Source provides access to the job runs and artifacts of a CI deployment.
+// Implementations stop outstanding requests when ctx is cancelled.
+type Source interface {
+	// ListRuns returns the most recent runs of a job, newest first. When timePeriod
+	// is non-zero only runs started within that period are returned, up to limit.
+	ListRuns(ctx context.Context, jobName string, limit int, timePeriod time.Duration) ([]JobRun, error)
+
+	// FetchProwJob fetches the status and type of a run from its prowjob.json
+	FetchProwJob(ctx context.Context, run *JobRun) (*ProwJobInfo, error)
+
+	// FetchArtifact fetches a file relative to the run's artifact directory
+	// (e.g. "artifacts/junit.functest.xml"). Missing artifacts return nil data and a nil error.
+	FetchArtifact(ctx context.Context, run *JobRun, path string) ([]byte, error)
+
+	// ListArtifacts lists the files of the run's artifact directory matching glob, as paths
+	// relative to that directory. In glob, "*" matches within a path segment and "**" also
+	// matches across segments (e.g. "artifacts/**junit*.xml").
+	ListArtifacts(ctx context.Context, run *JobRun, glob string) ([]string, error)
+
+	// FetchBuildLog fetches the raw build-log.txt of a run
+	FetchBuildLog(ctx context.Context, run *JobRun) ([]byte, error)
+}
@@ -0,0 +1,13 @@
+package compute
+
+var _ = Describe("[sig-compute]VM Lifecycle", decorators.Quarantine, func() {
+	It("[test_id:1525]should start", func() {})
+
+	Context("with a stopped VM", func() {
+		It("should stop", func() {})
+	})
+})
+
+var _ = Describe("[sig-compute]VM Pool", func() {
+	It("should scale", func() {})
+})
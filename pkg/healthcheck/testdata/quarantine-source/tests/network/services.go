@@ -0,0 +1,6 @@
+package network
+
+// The specs are declared by a helper, so only the container is known
+var _ = Describe("[sig-network]Services", Label("QUARANTINE"), func() {
+	describeServiceTests()
+})
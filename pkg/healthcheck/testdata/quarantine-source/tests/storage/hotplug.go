@@ -0,0 +1,12 @@
+package storage
+
+var _ = SIGDescribe("Hotplug", func() {
+	It("[QUARANTINE]should hotplug a disk", func() {})
+
+	It("should unplug a disk", func() {})
+
+	DescribeTable("with bus", Label("QUARANTINE"), func(bus string) {},
+		Entry("virtio", "virtio"),
+		Entry("[test_id:3000]scsi", "scsi"),
+	)
+})
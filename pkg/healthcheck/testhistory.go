@@ -0,0 +1,146 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// TestHistoryConfig selects the tests and runs a test history is built from
+type TestHistoryConfig struct {
+	TestRegex  *regexp.Regexp
+	TimePeriod time.Duration // Only runs started within this period are analyzed
+	RunLimit   int           // Maximum number of runs fetched per lane
+	Parallel   int           // Number of runs whose artifacts are fetched concurrently
+}
+
+// TestHistory is the cross-lane history of the tests matching a pattern
+type TestHistory struct {
+	Pattern    string             `json:"pattern"`
+	Window     string             `json:"window"`
+	Tests      []string           `json:"tests"` // Matching test names that ran
+	Executions int                `json:"executions"`
+	Passes     int                `json:"passes"`
+	Failures   int                `json:"failures"`
+	Lanes      []LaneTestHistory  `json:"lanes"`       // Most failures first
+	Signatures []SignatureCluster `json:"signatures"`  // Failure signature distribution across lanes
+	LaneErrors []LaneError        `json:"lane_errors"` // Lanes left out because their runs could not be fetched
+}
+
+// LaneTestHistory are the outcomes of the matching tests on one lane
+type LaneTestHistory struct {
+	JobName      string   `json:"job_name"`
+	Runs         int      `json:"runs"` // Runs in which a matching test ran
+	Executions   int      `json:"executions"`
+	Passes       int      `json:"passes"`
+	Failures     int      `json:"failures"`
+	Skips        int      `json:"skips"`
+	PassRate     float64  `json:"pass_rate"` // Percentage of executions that passed
+	FirstFailure string   `json:"first_failure,omitempty"`
+	LastFailure  string   `json:"last_failure,omitempty"`
+	FailureURLs  []string `json:"failure_urls,omitempty"`
+}
+
+// AnalyzeTestHistory fetches the runs of every lane within config.TimePeriod and reports
+// how the tests matching config.TestRegex did on each. Lanes without runs in the window or
+// where no matching test ran are left out, lanes whose runs cannot be fetched are listed
+// in LaneErrors.
+func AnalyzeTestHistory(ctx context.Context, source Source, lanes []string, config TestHistoryConfig) (*TestHistory, error) {
+	history := &TestHistory{
+		Pattern:    config.TestRegex.String(),
+		Window:     formatPeriod(config.TimePeriod),
+		Tests:      []string{},
+		Lanes:      []LaneTestHistory{},
+		LaneErrors: []LaneError{},
+	}
+	var failures []Testcase
+	testNames := make(map[string]bool)
+
+	for _, lane := range lanes {
+		runs, err := FetchJobHistoryWithTimePeriod(ctx, source, lane, config.TimePeriod, config.RunLimit)
+		if errors.Is(err, ErrNoJobHistory) {
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			history.LaneErrors = append(history.LaneErrors, LaneError{JobName: lane, Error: err.Error()})
+			continue
+		}
+
+		summary, err := AnalyzeLaneRuns(ctx, source, runs, config.Parallel)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			history.LaneErrors = append(history.LaneErrors, LaneError{JobName: lane, Error: fmt.Sprintf("failed to analyze runs: %v", err)})
+			continue
+		}
+
+		laneHistory := LaneTestHistory{JobName: lane}
+		for _, run := range summary.Runs {
+			ran := false
+			for _, testcase := range run.Passed {
+				if config.TestRegex.MatchString(testcase.Name) {
+					ran = true
+					laneHistory.Passes++
+					testNames[testcase.Name] = true
+				}
+			}
+			for _, testcase := range run.Skipped {
+				if config.TestRegex.MatchString(testcase.Name) {
+					laneHistory.Skips++
+				}
+			}
+			for _, testcase := range run.Failures {
+				if !config.TestRegex.MatchString(testcase.Name) {
+					continue
+				}
+				ran = true
+				laneHistory.Failures++
+				testNames[testcase.Name] = true
+				failures = append(failures, testcase)
+				if !containsString(laneHistory.FailureURLs, run.URL) {
+					laneHistory.FailureURLs = append(laneHistory.FailureURLs, run.URL)
+				}
+				if run.Timestamp != "" && (laneHistory.FirstFailure == "" || run.Timestamp < laneHistory.FirstFailure) {
+					laneHistory.FirstFailure = run.Timestamp
+				}
+				if run.Timestamp > laneHistory.LastFailure {
+					laneHistory.LastFailure = run.Timestamp
+				}
+			}
+			if ran {
+				laneHistory.Runs++
+			}
+		}
+
+		laneHistory.Executions = laneHistory.Passes + laneHistory.Failures
+		if laneHistory.Executions == 0 {
+			continue
+		}
+		laneHistory.PassRate = float64(laneHistory.Passes) / float64(laneHistory.Executions) * 100
+		history.Lanes = append(history.Lanes, laneHistory)
+		history.Executions += laneHistory.Executions
+		history.Passes += laneHistory.Passes
+		history.Failures += laneHistory.Failures
+	}
+
+	for name := range testNames {
+		history.Tests = append(history.Tests, name)
+	}
+	sort.Strings(history.Tests)
+	sort.SliceStable(history.Lanes, func(i, j int) bool {
+		if history.Lanes[i].Failures != history.Lanes[j].Failures {
+			return history.Lanes[i].Failures > history.Lanes[j].Failures
+		}
+		return history.Lanes[i].JobName < history.Lanes[j].JobName
+	})
+	history.Signatures = ClusterFailures(failures)
+
+	return history, nil
+}
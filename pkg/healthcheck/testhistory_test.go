@@ -0,0 +1,46 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// unreachableSource fails to list the runs of one lane and delegates the others
+type unreachableSource struct {
+	Source
+	lane string
+}
+
+func (s unreachableSource) ListRuns(ctx context.Context, jobName string, limit int, timePeriod time.Duration) ([]JobRun, error) {
+	if jobName == s.lane {
+		return nil, errors.New("failed to fetch job history: status code 503")
+	}
+	return s.Source.ListRuns(ctx, jobName, limit, timePeriod)
+}
+
+func TestAnalyzeTestHistoryReportsLaneErrors(t *testing.T) {
+	withNow(t, time.Date(2025, 8, 14, 12, 0, 0, 0, time.UTC))
+
+	source := unreachableSource{Source: NewLocalSource("testdata/quarantine"), lane: "pull-kubevirt-e2e-arm64"}
+	lanes := []string{"pull-kubevirt-e2e-arm64", "pull-kubevirt-e2e-k8s-1.33-sig-compute", "pull-kubevirt-e2e-k8s-1.33-sig-compute-idle"}
+	history, err := AnalyzeTestHistory(context.Background(), source, lanes, TestHistoryConfig{
+		TestRegex:  regexp.MustCompile(`should start a stopped VM`),
+		TimePeriod: 14 * 24 * time.Hour,
+		RunLimit:   100,
+		Parallel:   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history.Lanes) != 1 || history.Lanes[0].Passes != 2 {
+		t.Errorf("Lanes = %+v, want the sig-compute lane with 2 passes", history.Lanes)
+	}
+	// The idle lane has no runs, the arm64 lane could not be fetched
+	if len(history.LaneErrors) != 1 || history.LaneErrors[0].JobName != lanes[0] {
+		t.Errorf("LaneErrors = %+v, want only %s", history.LaneErrors, lanes[0])
+	}
+}
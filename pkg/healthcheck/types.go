@@ -17,13 +17,20 @@ type Job struct {
 	FailureURLs  []string `json:"FailureURLs"`
 }
 
+type Testsuites struct {
+	XMLName    xml.Name    `xml:"testsuites"`
+	Time       string      `xml:"time,attr"`
+	Testsuites []Testsuite `xml:"testsuite"`
+}
+
 type Testsuite struct {
-	XMLName  xml.Name   `xml:"testsuite"`
-	Failures string     `xml:"failures,attr"`
-	Name     string     `xml:"name,attr"`
-	Tests    string     `xml:"tests,attr"`
-	Time     string     `xml:"time,attr"`
-	Testcase []Testcase `xml:"testcase"`
+	XMLName    xml.Name    `xml:"testsuite"`
+	Failures   string      `xml:"failures,attr"`
+	Name       string      `xml:"name,attr"`
+	Tests      string      `xml:"tests,attr"`
+	Time       string      `xml:"time,attr"`
+	Testcase   []Testcase  `xml:"testcase"`
+	Testsuites []Testsuite `xml:"testsuite,omitempty"` // Nested suites
 }
 
 type Testcase struct {
@@ -32,9 +39,40 @@ type Testcase struct {
 	Name          string   `xml:"name,attr"`
 	Time          string   `xml:"time,attr"`
 	Failure       *Failure `xml:"failure,omitempty"`
+	Skipped       *Skipped `xml:"skipped,omitempty"`
 	URL           string   `xml:"url,omitempty"`
 	JobType       string   `xml:"-"` // Prow job type: presubmit, batch, postsubmit, periodic
 	IsQuarantined bool     `xml:"-"`
+	SourceFile    string   `xml:"-"` // Artifact path of the junit file the testcase was read from
+
+	// Fields only known from a Ginkgo JSON report
+	Labels          []string `xml:"-"` // Ginkgo labels of the spec and its containers
+	Location        string   `xml:"-"` // file:line of the spec
+	FailureNodeType string   `xml:"-"` // Ginkgo node that failed: It, BeforeEach, AfterEach, ...
+	FailureLocation string   `xml:"-"` // file:line the failure was raised at
+}
+
+// HasLabel reports whether the testcase carries the Ginkgo label
+func (t *Testcase) HasLabel(label string) bool {
+	for _, l := range t.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyLabel reports whether the testcase carries one of labels; an empty list matches every testcase
+func (t *Testcase) HasAnyLabel(labels []string) bool {
+	if len(labels) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		if t.HasLabel(label) {
+			return true
+		}
+	}
+	return false
 }
 
 type Failure struct {
@@ -44,6 +82,11 @@ type Failure struct {
 	Value   string   `xml:",chardata"`
 }
 
+type Skipped struct {
+	XMLName xml.Name `xml:"skipped"`
+	Message string   `xml:"message,attr,omitempty"`
+}
+
 type JobRun struct {
 	ID        string
 	URL       string
@@ -51,6 +94,11 @@ type JobRun struct {
 	JobType   string // Prow job type: presubmit, batch, postsubmit, periodic
 	Timestamp string
 	Failures  []Testcase
+	Passed    []Testcase `json:"-"` // Passed testcases, only used for per-test statistics
+	Skipped   []Testcase `json:"-"` // Skipped testcases, only used for per-test statistics
+	SuiteTime float64    // Wall time of the run's test suites in seconds, 0 when unknown
+	CommitSet string     // Code the run tested, from prowjob.json refs; empty when unknown
+	ProwJob   *ProwJob   `json:",omitempty"` // The run's prowjob.json, nil when unavailable
 }
 
 type LaneSummary struct {
@@ -64,6 +112,8 @@ type LaneSummary struct {
 	JobTypeStats  map[string]int // Breakdown of runs by job type (presubmit, batch, etc.)
 	JobTypeFailureRate map[string]float64 // Failure rate per job type
 	TestFailures  map[string]int
+	TestStats     map[string]TestStats // Outcomes of every test that ran, keyed by test name
+	Flakes        []TestFlakiness      // Tests that passed and failed on the same commit set
 	Runs          []JobRun
 	AllFailures   []Testcase  // All test failures across all runs
 	FailureRate   float64     // Percentage of runs that failed
@@ -73,6 +123,15 @@ type LaneSummary struct {
 	LastRunTime   string      // Timestamp of latest run
 }
 
+// TestStats are the outcomes of a single test across the runs of a lane
+type TestStats struct {
+	Executions int     `json:"executions"` // Runs in which the test passed or failed
+	Passes     int     `json:"passes"`
+	Failures   int     `json:"failures"`
+	Skips      int     `json:"skips"`
+	PassRate   float64 `json:"pass_rate"` // Percentage of executions that passed
+}
+
 type TestFailurePattern struct {
 	TestName    string
 	Count       int
@@ -86,4 +145,6 @@ type LaneDisplayConfig struct {
 	DisplayOnlyTestNames bool
 	DisplayFailures      bool
 	Summary              bool
+	GroupBySignature     bool // Cluster failures by normalized failure message instead of test name
+	Flakes               bool // List flaky tests with the runs proving their flakiness
 }
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -41,6 +42,9 @@ type LLMRunStatistics struct {
 	InfrastructureFailureRate float64 `json:"infrastructure_failure_rate_percent"`
 	TotalFailures             int     `json:"total_test_failures"`
 	UniqueTests               int     `json:"unique_failing_tests"`
+	TestsRun                  int     `json:"unique_tests_run"`
+	TestExecutions            int     `json:"test_executions"`
+	TestSkips                 int     `json:"test_skips"`
 }
 
 type LLMFailurePattern struct {
@@ -54,6 +58,13 @@ type LLMFailurePattern struct {
 	BuildLogContext  string   `json:"build_log_context,omitempty"`
 	PotentialCauses  []string `json:"potential_causes,omitempty"`
 	IsInfrastructure bool     `json:"is_infrastructure"`
+	Labels           []string `json:"labels,omitempty"`   // Ginkgo labels of the test
+	Location         string   `json:"location,omitempty"` // file:line of the Ginkgo spec
+
+	// Outcomes across all analyzed runs, only set when the runs had test results
+	Executions int      `json:"executions,omitempty"`
+	Skips      int      `json:"skips,omitempty"`
+	PassRate   *float64 `json:"pass_rate_percent,omitempty"`
 }
 
 type LLMTrends struct {
@@ -92,6 +103,13 @@ type LLMTestFailure struct {
 	StackTrace   string `json:"stack_trace,omitempty"`
 	ErrorMessage string `json:"error_message,omitempty"`
 	Duration     string `json:"duration,omitempty"`
+	SourceFile   string `json:"junit_file,omitempty"`
+
+	// Only known when the run published a Ginkgo JSON report
+	Labels          []string `json:"labels,omitempty"`
+	Location        string   `json:"location,omitempty"`
+	FailureNodeType string   `json:"failure_node_type,omitempty"`
+	FailureLocation string   `json:"failure_location,omitempty"`
 }
 
 type LLMFailureSummary struct {
@@ -111,8 +129,9 @@ type LLMMergeAnalysis struct {
 }
 
 type LLMFilter struct {
-	JobFilter  string `json:"job_filter"`
-	TestFilter string `json:"test_filter"`
+	JobFilter  string   `json:"job_filter"`
+	TestFilter string   `json:"test_filter"`
+	Labels     []string `json:"labels,omitempty"`
 }
 
 type LLMMergeStatistics struct {
@@ -264,9 +283,19 @@ type LLMTrendAnalysis struct {
 }
 
 type LLMFlakinessAnalysis struct {
-	FlakyTests      []string `json:"flaky_tests"`
-	FlakinessScore  float64  `json:"flakiness_score"`
-	PatternDetected bool     `json:"pattern_detected"`
+	FlakyTests      []LLMFlakyTest `json:"flaky_tests"`
+	FlakinessScore  float64        `json:"flakiness_score"`         // Percentage of failing tests that flaked
+	PatternDetected bool           `json:"pattern_detected"`
+	RunsWithCommits int            `json:"runs_with_known_commits"` // Runs whose tested commits are known
+}
+
+// LLMFlakyTest is a test that passed and failed on the same commit set
+type LLMFlakyTest struct {
+	TestName         string                      `json:"test_name"`
+	FlakeScore       float64                     `json:"flake_score"` // Percentage of failing commit sets on which the test also passed
+	FlakyCommitSets  int                         `json:"flaky_commit_sets"`
+	FailedCommitSets int                         `json:"failed_commit_sets"`
+	Evidence         []healthcheck.FlakeEvidence `json:"evidence"`
 }
 
 type LLMTrendFailurePattern struct {
@@ -279,23 +308,42 @@ type LLMTrendFailurePattern struct {
 type LLMCorrelationAnalysis struct {
 	JobPattern          string                  `json:"job_pattern"`
 	TimeWindow          string                  `json:"time_window"`
+	LanesAnalyzed       int                     `json:"lanes_analyzed"`
 	CorrelatedFailures  []LLMCorrelatedFailure  `json:"correlated_failures"`
+	SharedSignatures    []LLMSharedSignature    `json:"shared_signatures"`
 	EnvironmentAnalysis LLMEnvironmentAnalysis  `json:"environment_analysis"`
 	SystemicIssues      []LLMSystemicIssue      `json:"systemic_issues"`
 	Recommendations     []string                `json:"recommendations"`
+	FailedLanes         []healthcheck.LaneError `json:"failed_lanes"` // Matching lanes whose runs could not be fetched, left out of the analysis
 }
 
 type LLMCorrelatedFailure struct {
 	TestName         string   `json:"test_name"`
 	AffectedJobs     []string `json:"affected_jobs"`
-	CorrelationScore float64  `json:"correlation_score"`
-	Pattern          string   `json:"pattern"`
+	CorrelationScore float64  `json:"correlation_score"` // Fraction of the lanes running the test in which it failed
+	Pattern          string   `json:"pattern"`           // all_lanes, most_lanes or some_lanes
+	Failures         int      `json:"failures"`
+	Signature        string   `json:"signature"`
+	FirstFailure     string   `json:"first_failure,omitempty"`
+	LastFailure      string   `json:"last_failure,omitempty"`
+	EvidenceURLs     []string `json:"evidence_urls"`
+}
+
+// LLMSharedSignature is a failure signature seen in several lanes
+type LLMSharedSignature struct {
+	Signature    string   `json:"signature"`
+	Failures     int      `json:"failures"`
+	Tests        []string `json:"tests"`
+	AffectedJobs []string `json:"affected_jobs"`
+	EvidenceURLs []string `json:"evidence_urls"`
 }
 
 type LLMEnvironmentAnalysis struct {
-	ArchitectureFailures map[string]int `json:"architecture_failures"`
-	KubernetesVersions   map[string]int `json:"kubernetes_versions"`
-	ResourceIssues       []string       `json:"resource_issues"`
+	ArchitectureFailures          map[string]int     `json:"architecture_failures"` // Failed runs per architecture
+	ArchitectureFailureRates      map[string]float64 `json:"architecture_failure_rates"`
+	KubernetesVersions            map[string]int     `json:"kubernetes_versions"` // Failed runs per Kubernetes version
+	KubernetesVersionFailureRates map[string]float64 `json:"kubernetes_version_failure_rates"`
+	ResourceIssues                []string           `json:"resource_issues"`
 }
 
 type LLMSystemicIssue struct {
@@ -303,28 +351,56 @@ type LLMSystemicIssue struct {
 	Description  string   `json:"description"`
 	AffectedJobs []string `json:"affected_jobs"`
 	Severity     string   `json:"severity"`
+	EvidenceURLs []string `json:"evidence_urls"`
 }
 
 type LLMQuarantineAnalysis struct {
 	Scope                   string                        `json:"scope"`
+	Window                  string                        `json:"window"`
+	QuarantineLanes         []string                      `json:"quarantine_lanes"`
+	RegularLanesAnalyzed    int                           `json:"regular_lanes_analyzed"`
 	TotalQuarantinedTests   int                           `json:"total_quarantined_tests"`
-	QuarantineEffectiveness string                        `json:"quarantine_effectiveness"`
+	QuarantineEffectiveness string                        `json:"quarantine_effectiveness"` // Share of quarantined test failures kept in quarantine lanes
 	ActiveQuarantines       []LLMQuarantineStatus         `json:"active_quarantines"`
+	QuarantineCandidates    []LLMQuarantineCandidate      `json:"quarantine_candidates"`
 	RecommendedActions      []LLMQuarantineRecommendation `json:"recommended_actions"`
+	FailedLanes             []healthcheck.LaneError       `json:"failed_lanes"` // Lanes whose runs could not be fetched, left out of the analysis
 }
 
 type LLMQuarantineStatus struct {
-	TestName           string  `json:"test_name"`
-	Status             string  `json:"status"`
-	EffectivenessScore float64 `json:"effectiveness_score"`
-	RecommendedAction  string  `json:"recommended_action"`
+	TestName               string   `json:"test_name"`
+	TestID                 string   `json:"test_id,omitempty"`
+	SIG                    string   `json:"sig,omitempty"`
+	QuarantinedSince       string   `json:"quarantined_since,omitempty"`
+	Status                 string   `json:"status"`
+	EffectivenessScore     float64  `json:"effectiveness_score"` // Fraction of the failures that happened in quarantine lanes
+	RecommendedAction      string   `json:"recommended_action"`
+	QuarantineLaneRuns     int      `json:"quarantine_lane_runs"`
+	QuarantineLaneFailures int      `json:"quarantine_lane_failures"`
+	RegularLaneRuns        int      `json:"regular_lane_runs"`
+	RegularLaneFailures    int      `json:"regular_lane_failures"`
+	StableSince            string   `json:"stable_since"`  // Later of the quarantine date and the window start
+	StablePasses           int      `json:"stable_passes"` // Passes since stable_since, without a failure when stable
+	LastFailure            string   `json:"last_failure,omitempty"`
+	EvidenceURLs           []string `json:"evidence_urls"`
+}
+
+type LLMQuarantineCandidate struct {
+	TestName        string   `json:"test_name"`
+	Lanes           []string `json:"lanes"`
+	Runs            int      `json:"runs"`
+	Failures        int      `json:"failures"`
+	FlakeRate       float64  `json:"flake_rate"`
+	FlakyCommitSets int      `json:"flaky_commit_sets"`
+	EvidenceURLs    []string `json:"evidence_urls"`
 }
 
 type LLMQuarantineRecommendation struct {
-	TestName    string `json:"test_name"`
-	Action      string `json:"action"`
-	Reasoning   string `json:"reasoning"`
-	Priority    string `json:"priority"`
+	TestName     string   `json:"test_name"`
+	Action       string   `json:"action"`
+	Reasoning    string   `json:"reasoning"`
+	Priority     string   `json:"priority"`
+	EvidenceURLs []string `json:"evidence_urls"`
 }
 
 type LLMImpactAssessment struct {
@@ -385,7 +461,7 @@ type LLMReportTrends struct {
 }
 
 // formatLaneSummaryForLLM converts lane summary to LLM-optimized format
-func formatLaneSummaryForLLM(jobName string, summary *healthcheck.LaneSummary, includeDetails bool) LLMJobAnalysis {
+func formatLaneSummaryForLLM(ctx context.Context, source healthcheck.Source, jobName string, summary *healthcheck.LaneSummary, includeDetails bool) LLMJobAnalysis {
 	analysis := LLMJobAnalysis{
 		JobName: jobName,
 		TimeRange: LLMTimeRange{
@@ -416,6 +492,12 @@ func formatLaneSummaryForLLM(jobName string, summary *healthcheck.LaneSummary, i
 		Summary: generateAnalysisSummary(jobName, summary),
 	}
 
+	analysis.Statistics.TestsRun = len(summary.TestStats)
+	for _, stats := range summary.TestStats {
+		analysis.Statistics.TestExecutions += stats.Executions
+		analysis.Statistics.TestSkips += stats.Skips
+	}
+
 	if includeDetails {
 		// Add top failures
 		analysis.TopFailures = make([]LLMFailurePattern, 0, len(summary.TopFailures))
@@ -428,13 +510,20 @@ func formatLaneSummaryForLLM(jobName string, summary *healthcheck.LaneSummary, i
 				PotentialCauses:  inferPotentialCauses(failure.TestName),
 				IsInfrastructure: isInfrastructureFailure(failure.Category),
 			}
+			pattern.Labels, pattern.Location = ginkgoMetadata(summary.AllFailures, failure.TestName)
+			if stats, ok := summary.TestStats[failure.TestName]; ok && stats.Executions > 0 {
+				passRate := stats.PassRate
+				pattern.Executions = stats.Executions
+				pattern.Skips = stats.Skips
+				pattern.PassRate = &passRate
+			}
 			
 			// For infrastructure failures, try to fetch build log context
 			if pattern.IsInfrastructure && includeDetails {
 				// Find a representative job URL for this failure type
 				for _, testFailure := range summary.AllFailures {
 					if testFailure.Name == failure.TestName && testFailure.URL != "" {
-						if buildLog, err := healthcheck.FetchBuildLogContext(testFailure.URL); err == nil {
+						if buildLog, err := healthcheck.FetchBuildLogContext(ctx, source, testFailure.URL); err == nil {
 							pattern.BuildLogContext = buildLog
 						}
 						break // Only fetch for one representative failure
@@ -471,7 +560,7 @@ func formatLaneSummaryForLLM(jobName string, summary *healthcheck.LaneSummary, i
 }
 
 // formatJobFailuresForLLM converts job runs to LLM-optimized format
-func formatJobFailuresForLLM(jobName string, runs []healthcheck.JobRun, includeStackTraces bool) LLMJobFailures {
+func formatJobFailuresForLLM(ctx context.Context, source healthcheck.Source, jobName string, runs []healthcheck.JobRun, includeStackTraces bool) LLMJobFailures {
 	llmRuns := make([]LLMJobRun, 0, len(runs))
 	totalFailures := 0
 	failuresByRun := make(map[string]int)
@@ -484,6 +573,11 @@ func formatJobFailuresForLLM(jobName string, runs []healthcheck.JobRun, includeS
 				TestName:     failure.Name,
 				Category:     categorizeTestName(failure.Name),
 				ErrorMessage: extractErrorMessage(failure.Failure),
+				SourceFile:   failure.SourceFile,
+				Labels:          failure.Labels,
+				Location:        failure.Location,
+				FailureNodeType: failure.FailureNodeType,
+				FailureLocation: failure.FailureLocation,
 			}
 			
 			if includeStackTraces && failure.Failure != nil {
@@ -506,7 +600,7 @@ func formatJobFailuresForLLM(jobName string, runs []healthcheck.JobRun, includeS
 		
 		// For infrastructure failures, fetch build log context
 		if llmRun.IsInfrastructure && includeStackTraces {
-			if buildLog, err := healthcheck.FetchBuildLogContext(run.URL); err == nil {
+			if buildLog, err := healthcheck.FetchBuildLogContext(ctx, source, run.URL); err == nil {
 				llmRun.BuildLogContext = buildLog
 			}
 		}
@@ -557,7 +651,7 @@ func formatJobFailuresForLLM(jobName string, runs []healthcheck.JobRun, includeS
 }
 
 // formatMergeFailuresForLLM converts merge analysis to LLM-optimized format
-func formatMergeFailuresForLLM(result *healthcheck.ProcessorResult, jobFilter, testFilter string) LLMMergeAnalysis {
+func formatMergeFailuresForLLM(result *healthcheck.ProcessorResult, jobFilter, testFilter string, labels []string) LLMMergeAnalysis {
 	totalFailures := 0
 	affectedJobs := make(map[string]bool)
 	categories := make(map[string]int)
@@ -593,6 +687,7 @@ func formatMergeFailuresForLLM(result *healthcheck.ProcessorResult, jobFilter, t
 			Category:        category,
 			PotentialCauses: inferPotentialCauses(testName),
 		}
+		pattern.Labels, pattern.Location = ginkgoMetadata(testcases, testName)
 		patterns = append(patterns, pattern)
 
 		// Update by-job summary
@@ -624,6 +719,7 @@ func formatMergeFailuresForLLM(result *healthcheck.ProcessorResult, jobFilter, t
 		Filter: LLMFilter{
 			JobFilter:  jobFilter,
 			TestFilter: testFilter,
+			Labels:     labels,
 		},
 		Statistics: LLMMergeStatistics{
 			TotalFailures: totalFailures,
@@ -1227,6 +1323,16 @@ func isInfrastructureFailure(category string) bool {
 	return category == "infrastructure" || category == "infra-timeout" || category == "infra-error"
 }
 
+// ginkgoMetadata returns the Ginkgo labels and location of the first testcase named testName
+func ginkgoMetadata(testcases []healthcheck.Testcase, testName string) ([]string, string) {
+	for _, testcase := range testcases {
+		if testcase.Name == testName && (len(testcase.Labels) > 0 || testcase.Location != "") {
+			return testcase.Labels, testcase.Location
+		}
+	}
+	return nil, ""
+}
+
 // isInfrastructureRun determines if a job run represents an infrastructure failure
 func isInfrastructureRun(run healthcheck.JobRun) bool {
 	// Infrastructure failure if the job failed without test failures (i.e., build/environment issues)
@@ -1,10 +1,8 @@
 package mcp
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"regexp"
 	"sort"
 	"strconv"
@@ -14,12 +12,21 @@ import (
 	"healthcheck/pkg/healthcheck"
 )
 
+// parseLabels splits a comma-separated list of Ginkgo labels
+func parseLabels(value string) []string {
+	var labels []string
+	for _, label := range strings.Split(value, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
 // buildProcessorConfig creates a ProcessorConfig from MCP parameters
 func buildProcessorConfig(jobFilter, testFilter string, includeQuarantine bool) (healthcheck.ProcessorConfig, error) {
 	// Handle job aliases
-	if alias, exists := healthcheck.JobRegexAliases[jobFilter]; exists {
-		jobFilter = alias
-	}
+	jobFilter = healthcheck.ResolveJobAlias(jobFilter)
 
 	// Compile regex patterns
 	jobRegex, err := regexp.Compile(jobFilter)
@@ -50,7 +57,7 @@ func searchPatternsInResults(results *healthcheck.Results, pattern, jobFilter, s
 		return matches
 	}
 
-	jobRegex, err := regexp.Compile(jobFilter)
+	jobRegex, err := regexp.Compile(healthcheck.ResolveJobAlias(jobFilter))
 	if err != nil {
 		return matches
 	}
@@ -462,7 +469,7 @@ func parseStackTraceFrame(line string) LLMStackTraceFrame {
 }
 
 // ExtractRepositoryInfo extracts repository and commit information from job URL
-func ExtractRepositoryInfo(jobURL string) (LLMRepositoryInfo, error) {
+func ExtractRepositoryInfo(ctx context.Context, source healthcheck.Source, jobURL string) (LLMRepositoryInfo, error) {
 	var repoInfo LLMRepositoryInfo
 	
 	// Example URL: https://prow.ci.kubevirt.io/view/gs/kubevirt-prow/pr-logs/pull/kubevirt_kubevirt/15472/pull-kubevirt-unit-test-arm64/1958099225396908032
@@ -487,49 +494,26 @@ func ExtractRepositoryInfo(jobURL string) (LLMRepositoryInfo, error) {
 	repoInfo.Commit = "main"  // Default fallback
 	
 	// Try to fetch commit and PR info from prowjob.json if available
-	if gsPattern := regexp.MustCompile(`/view/gs/(.+)$`); gsPattern.MatchString(jobURL) {
-		gsMatches := gsPattern.FindStringSubmatch(jobURL)
-		if len(gsMatches) >= 2 {
-			prowjobURL := fmt.Sprintf("https://storage.googleapis.com/%s/prowjob.json", gsMatches[1])
-			prowjobInfo := fetchProwjobInfo(prowjobURL)
-			
-			if prowjobInfo.CommitHash != "" {
-				repoInfo.Commit = prowjobInfo.CommitHash
-				repoInfo.PRInfo = prowjobInfo.PRInfo
-				repoInfo.Branch = prowjobInfo.PRInfo.HeadRef
-			}
-		}
+	prowjobInfo := fetchProwjobInfo(ctx, source, jobURL)
+	if prowjobInfo.CommitHash != "" {
+		repoInfo.Commit = prowjobInfo.CommitHash
+		repoInfo.PRInfo = prowjobInfo.PRInfo
+		repoInfo.Branch = prowjobInfo.PRInfo.HeadRef
 	}
 	
 	return repoInfo, nil
 }
 
 // extractCommitFromProwJob attempts to extract commit hash from prowjob.json
-func extractCommitFromProwJob(jobURL string) string {
-	// Convert job URL to prowjob.json URL
-	// Example: https://prow.ci.kubevirt.io/view/gs/kubevirt-prow/pr-logs/pull/kubevirt_kubevirt/15472/pull-kubevirt-unit-test-arm64/1958099225396908032
-	// Becomes: https://storage.googleapis.com/kubevirt-prow/pr-logs/pull/kubevirt_kubevirt/15472/pull-kubevirt-unit-test-arm64/1958099225396908032/prowjob.json
-	
-	// Extract the gs path from the prow URL
-	gsPattern := regexp.MustCompile(`/view/gs/(.+)$`)
-	matches := gsPattern.FindStringSubmatch(jobURL)
-	
-	if len(matches) < 2 {
-		return "" // Could not extract gs path
-	}
-	
-	// Construct prowjob.json URL
-	prowjobURL := fmt.Sprintf("https://storage.googleapis.com/%s/prowjob.json", matches[1])
-	
+func extractCommitFromProwJob(ctx context.Context, source healthcheck.Source, jobURL string) string {
 	// Try to fetch and parse the prowjob.json
-	commit := fetchCommitFromProwjobJSON(prowjobURL)
+	commit := fetchCommitFromProwjobJSON(ctx, source, jobURL)
 	if commit != "" {
 		return commit
 	}
 	
-	// Fallback: try to extract from artifacts/junit XML or build-log.txt
-	buildLogURL := fmt.Sprintf("https://storage.googleapis.com/%s/build-log.txt", matches[1])
-	commit = extractCommitFromBuildLog(buildLogURL)
+	// Fallback: try to extract from build-log.txt
+	commit = extractCommitFromBuildLog(ctx, source, jobURL)
 	
 	return commit
 }
@@ -541,61 +525,32 @@ type ProwjobInfo struct {
 }
 
 // fetchCommitFromProwjobJSON fetches and parses prowjob.json to extract commit hash
-func fetchCommitFromProwjobJSON(prowjobURL string) string {
-	info := fetchProwjobInfo(prowjobURL)
+func fetchCommitFromProwjobJSON(ctx context.Context, source healthcheck.Source, jobURL string) string {
+	info := fetchProwjobInfo(ctx, source, jobURL)
 	return info.CommitHash
 }
 
 // fetchProwjobInfo fetches and parses prowjob.json to extract full PR information
-func fetchProwjobInfo(prowjobURL string) ProwjobInfo {
+func fetchProwjobInfo(ctx context.Context, source healthcheck.Source, jobURL string) ProwjobInfo {
 	var info ProwjobInfo
 	
-	// Make HTTP request with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(prowjobURL)
-	if err != nil {
-		return info // Could not fetch prowjob.json
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
+	body, err := source.FetchArtifact(ctx, &healthcheck.JobRun{URL: jobURL}, "prowjob.json")
+	if err != nil || body == nil {
 		return info // prowjob.json not found or accessible
 	}
 	
-	// Read and parse JSON
-	body, err := io.ReadAll(resp.Body)
+	prowjob, err := healthcheck.ParseProwJob(body)
 	if err != nil {
 		return info
 	}
-	
-	// Parse prowjob JSON structure with full PR information
-	var prowjob struct {
-		Spec struct {
-			Refs struct {
-				Org    string `json:"org"`
-				Repo   string `json:"repo"`
-				Pulls  []struct {
-					Number     int    `json:"number"`
-					Author     string `json:"author"`
-					SHA        string `json:"sha"`
-					Title      string `json:"title"`
-					HeadRef    string `json:"head_ref"`
-					Link       string `json:"link"`
-					CommitLink string `json:"commit_link"`
-					AuthorLink string `json:"author_link"`
-				} `json:"pulls"`
-				BaseSHA string `json:"base_sha"`
-			} `json:"refs"`
-		} `json:"spec"`
-	}
-	
-	if err := json.Unmarshal(body, &prowjob); err != nil {
+	refs := prowjob.TestedRefs()
+	if refs == nil {
 		return info
 	}
 	
 	// Extract commit SHA and PR information
-	if len(prowjob.Spec.Refs.Pulls) > 0 {
-		pull := prowjob.Spec.Refs.Pulls[0]
+	if len(refs.Pulls) > 0 {
+		pull := refs.Pulls[0]
 		info.CommitHash = pull.SHA
 		
 		// Populate PR information
@@ -610,45 +565,36 @@ func fetchProwjobInfo(prowjobURL string) ProwjobInfo {
 		}
 		
 		// Determine if this is from a fork by checking if author != org
-		info.PRInfo.IsFromFork = pull.Author != prowjob.Spec.Refs.Org
+		info.PRInfo.IsFromFork = pull.Author != refs.Org
 		
 		if info.PRInfo.IsFromFork {
 			// This is from a fork
-			info.PRInfo.HeadRepo = prowjob.Spec.Refs.Repo
+			info.PRInfo.HeadRepo = refs.Repo
 			info.PRInfo.HeadRepoOwner = pull.Author
-			info.PRInfo.ForkRemoteURL = fmt.Sprintf("https://github.com/%s/%s.git", pull.Author, prowjob.Spec.Refs.Repo)
+			info.PRInfo.ForkRemoteURL = fmt.Sprintf("https://github.com/%s/%s.git", pull.Author, refs.Repo)
 		} else {
 			// This is from the same repository
-			info.PRInfo.HeadRepo = prowjob.Spec.Refs.Repo
-			info.PRInfo.HeadRepoOwner = prowjob.Spec.Refs.Org
+			info.PRInfo.HeadRepo = refs.Repo
+			info.PRInfo.HeadRepoOwner = refs.Org
 			info.PRInfo.ForkRemoteURL = "" // Not needed for same repo
 		}
-	} else if prowjob.Spec.Refs.BaseSHA != "" {
-		info.CommitHash = prowjob.Spec.Refs.BaseSHA
+	} else if refs.BaseSHA != "" {
+		info.CommitHash = refs.BaseSHA
 	}
 	
 	return info
 }
 
 // extractCommitFromBuildLog extracts commit hash from build log as fallback
-func extractCommitFromBuildLog(buildLogURL string) string {
-	// Make HTTP request with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(buildLogURL)
+func extractCommitFromBuildLog(ctx context.Context, source healthcheck.Source, jobURL string) string {
+	body, err := source.FetchBuildLog(ctx, &healthcheck.JobRun{URL: jobURL})
 	if err != nil {
 		return ""
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return ""
-	}
 	
-	// Read first few KB of build log to find commit references
-	limitedReader := io.LimitReader(resp.Body, 8192) // Read first 8KB
-	body, err := io.ReadAll(limitedReader)
-	if err != nil {
-		return ""
+	// Only the first few KB of build log are needed to find commit references
+	if len(body) > 8192 {
+		body = body[:8192]
 	}
 	
 	logContent := string(body)
@@ -718,27 +664,31 @@ func analyzeTrendsFromRuns(runs []healthcheck.JobRun, includeFlakiness bool) LLM
 	return analysis
 }
 
-// analyzeFailureCorrelationAcrossJobs analyzes failure correlation across multiple jobs
-func analyzeFailureCorrelationAcrossJobs(results *healthcheck.Results, jobPattern, timeWindow string, includeEnvironmentAnalysis bool) LLMCorrelationAnalysis {
+// analyzeFailureCorrelationAcrossJobs turns the failures correlated across lanes into
+// systemic issues and recommendations
+func analyzeFailureCorrelationAcrossJobs(correlation *healthcheck.FailureCorrelation, jobPattern, timeWindow string, includeEnvironmentAnalysis bool) LLMCorrelationAnalysis {
 	analysis := LLMCorrelationAnalysis{
 		JobPattern:         jobPattern,
 		TimeWindow:         timeWindow,
+		LanesAnalyzed:      len(correlation.Lanes),
 		CorrelatedFailures: []LLMCorrelatedFailure{},
+		SharedSignatures:   []LLMSharedSignature{},
 		EnvironmentAnalysis: LLMEnvironmentAnalysis{},
 		SystemicIssues:     []LLMSystemicIssue{},
 		Recommendations:    []string{},
+		FailedLanes:        correlation.LaneErrors,
 	}
 
 	// Analyze job failures for correlation patterns
-	correlatedFailures := findCorrelatedFailures(results, jobPattern)
-	analysis.CorrelatedFailures = correlatedFailures
+	analysis.CorrelatedFailures = findCorrelatedFailures(correlation)
+	analysis.SharedSignatures = findSharedSignatures(correlation)
 
 	if includeEnvironmentAnalysis {
-		analysis.EnvironmentAnalysis = analyzeEnvironmentSpecificFailures(results, jobPattern)
+		analysis.EnvironmentAnalysis = analyzeEnvironmentSpecificFailures(correlation)
 	}
 
 	// Identify systemic issues
-	analysis.SystemicIssues = identifySystemicIssues(correlatedFailures)
+	analysis.SystemicIssues = identifySystemicIssues(correlation, analysis.CorrelatedFailures, analysis.SharedSignatures, includeEnvironmentAnalysis)
 
 	// Generate recommendations
 	analysis.Recommendations = generateCorrelationRecommendations(analysis)
@@ -746,27 +696,35 @@ func analyzeFailureCorrelationAcrossJobs(results *healthcheck.Results, jobPatter
 	return analysis
 }
 
-// analyzeQuarantineEffectiveness analyzes quarantine effectiveness and provides recommendations
-func analyzeQuarantineEffectiveness(quarantinedTests map[string]bool, results *healthcheck.Results, scope string, includeRecommendations bool) LLMQuarantineAnalysis {
+// analyzeQuarantineEffectiveness rates how well quarantined tests are kept out of regular
+// lanes and recommends which tests to un-quarantine, fix or quarantine
+func analyzeQuarantineEffectiveness(report *healthcheck.QuarantineReport, scope string, includeRecommendations bool) LLMQuarantineAnalysis {
 	analysis := LLMQuarantineAnalysis{
-		Scope:                  scope,
-		TotalQuarantinedTests:  len(quarantinedTests),
+		Scope:                   scope,
+		Window:                  report.Window,
+		QuarantineLanes:         report.QuarantineLanes,
+		RegularLanesAnalyzed:    len(report.RegularLanes),
+		TotalQuarantinedTests:   len(report.Quarantined),
 		QuarantineEffectiveness: "unknown",
-		ActiveQuarantines:      []LLMQuarantineStatus{},
-		RecommendedActions:     []LLMQuarantineRecommendation{},
+		ActiveQuarantines:       []LLMQuarantineStatus{},
+		QuarantineCandidates:    []LLMQuarantineCandidate{},
+		RecommendedActions:      []LLMQuarantineRecommendation{},
+		FailedLanes:             report.LaneErrors,
 	}
 
 	// Analyze quarantine status for each test
-	for testName := range quarantinedTests {
-		status := analyzeQuarantineStatus(testName, results)
-		analysis.ActiveQuarantines = append(analysis.ActiveQuarantines, status)
+	for _, test := range report.Quarantined {
+		analysis.ActiveQuarantines = append(analysis.ActiveQuarantines, analyzeQuarantineStatus(test))
+	}
+	for _, candidate := range report.FlakyCandidates {
+		analysis.QuarantineCandidates = append(analysis.QuarantineCandidates, quarantineCandidate(candidate))
 	}
 
 	// Calculate overall effectiveness
 	analysis.QuarantineEffectiveness = calculateQuarantineEffectiveness(analysis.ActiveQuarantines)
 
 	if includeRecommendations {
-		analysis.RecommendedActions = generateQuarantineRecommendations(analysis)
+		analysis.RecommendedActions = generateQuarantineRecommendations(analysis, report.Window)
 	}
 
 	return analysis
@@ -854,56 +812,44 @@ func analyzeTrendDirection(runs []healthcheck.JobRun) string {
 	return "stable"
 }
 
+// analyzeFlakinessPatterns reports the tests that passed and failed on the same commit set.
+// The flakiness score is the percentage of tests failing on known commits that also passed on them.
 func analyzeFlakinessPatterns(runs []healthcheck.JobRun) LLMFlakinessAnalysis {
 	flakiness := LLMFlakinessAnalysis{
-		FlakyTests:        []string{},
+		FlakyTests:        []LLMFlakyTest{},
 		FlakinessScore:    0.0,
 		PatternDetected:   false,
 	}
 
-	// Analyze test consistency across runs
-	testResults := make(map[string][]string)
-	
+	failingTests := make(map[string]bool)
 	for _, run := range runs {
+		if run.CommitSet == "" {
+			continue
+		}
+		flakiness.RunsWithCommits++
 		for _, failure := range run.Failures {
-			testResults[failure.Name] = append(testResults[failure.Name], run.Status)
+			failingTests[failure.Name] = true
 		}
 	}
 
-	// Calculate flakiness for each test
-	flakyCount := 0
-	for testName, results := range testResults {
-		if isFlaky(results) {
-			flakiness.FlakyTests = append(flakiness.FlakyTests, testName)
-			flakyCount++
-		}
+	for _, flake := range healthcheck.DetectFlakes(runs) {
+		flakiness.FlakyTests = append(flakiness.FlakyTests, LLMFlakyTest{
+			TestName:         flake.TestName,
+			FlakeScore:       flake.FlakeScore,
+			FlakyCommitSets:  flake.FlakyCommitSets,
+			FailedCommitSets: flake.FailedCommitSets,
+			Evidence:         flake.Evidence,
+		})
 	}
 
-	if len(testResults) > 0 {
-		flakiness.FlakinessScore = float64(flakyCount) / float64(len(testResults)) * 100
+	if len(failingTests) > 0 {
+		flakiness.FlakinessScore = float64(len(flakiness.FlakyTests)) / float64(len(failingTests)) * 100
 		flakiness.PatternDetected = flakiness.FlakinessScore > 20 // 20% threshold
 	}
 
 	return flakiness
 }
 
-func isFlaky(results []string) bool {
-	if len(results) < 3 {
-		return false
-	}
-	
-	failures := 0
-	for _, result := range results {
-		if result == "FAILURE" {
-			failures++
-		}
-	}
-	
-	failureRate := float64(failures) / float64(len(results))
-	// Consider flaky if failure rate is between 10% and 90%
-	return failureRate > 0.1 && failureRate < 0.9
-}
-
 func analyzeFailurePatternsOverTime(runs []healthcheck.JobRun) []LLMTrendFailurePattern {
 	patterns := []LLMTrendFailurePattern{}
 	
@@ -1001,47 +947,388 @@ func generateTrendRecommendations(analysis LLMTrendAnalysis) []string {
 	return recommendations
 }
 
-// Placeholder implementations for correlation analysis
-func findCorrelatedFailures(results *healthcheck.Results, jobPattern string) []LLMCorrelatedFailure {
-	// Simplified implementation
-	return []LLMCorrelatedFailure{}
+// maxEvidenceURLs is the number of run URLs given as evidence of a correlation or issue
+const maxEvidenceURLs = 10
+
+// findCorrelatedFailures lists the tests failing in several lanes within the window
+func findCorrelatedFailures(correlation *healthcheck.FailureCorrelation) []LLMCorrelatedFailure {
+	failures := []LLMCorrelatedFailure{}
+	for _, test := range correlation.Tests {
+		pattern := "some_lanes"
+		if test.CorrelationScore >= 1 {
+			pattern = "all_lanes"
+		} else if test.CorrelationScore >= 0.5 {
+			pattern = "most_lanes"
+		}
+		failures = append(failures, LLMCorrelatedFailure{
+			TestName:         test.TestName,
+			AffectedJobs:     test.FailedLanes,
+			CorrelationScore: test.CorrelationScore,
+			Pattern:          pattern,
+			Failures:         test.Failures,
+			Signature:        test.Signature,
+			FirstFailure:     test.FirstFailure,
+			LastFailure:      test.LastFailure,
+			EvidenceURLs:     limitURLs(test.EvidenceURLs),
+		})
+	}
+	return failures
+}
+
+// findSharedSignatures lists the failure signatures seen in several lanes
+func findSharedSignatures(correlation *healthcheck.FailureCorrelation) []LLMSharedSignature {
+	signatures := []LLMSharedSignature{}
+	for _, cluster := range correlation.Signatures {
+		signatures = append(signatures, LLMSharedSignature{
+			Signature:    cluster.Signature,
+			Failures:     cluster.Count,
+			Tests:        cluster.Tests,
+			AffectedJobs: cluster.Lanes,
+			EvidenceURLs: limitURLs(cluster.URLs),
+		})
+	}
+	return signatures
 }
 
-func analyzeEnvironmentSpecificFailures(results *healthcheck.Results, jobPattern string) LLMEnvironmentAnalysis {
-	return LLMEnvironmentAnalysis{
-		ArchitectureFailures: map[string]int{},
-		KubernetesVersions:   map[string]int{},
-		ResourceIssues:       []string{},
+// analyzeEnvironmentSpecificFailures breaks the failed runs down by the architecture and
+// Kubernetes version of their lanes, and lists lanes failing mostly for infrastructure reasons
+func analyzeEnvironmentSpecificFailures(correlation *healthcheck.FailureCorrelation) LLMEnvironmentAnalysis {
+	analysis := LLMEnvironmentAnalysis{
+		ArchitectureFailures:          map[string]int{},
+		ArchitectureFailureRates:      map[string]float64{},
+		KubernetesVersions:            map[string]int{},
+		KubernetesVersionFailureRates: map[string]float64{},
+		ResourceIssues:                []string{},
+	}
+
+	for _, env := range correlation.Environments {
+		switch env.Dimension {
+		case "architecture":
+			analysis.ArchitectureFailures[env.Value] = env.FailedRuns
+			analysis.ArchitectureFailureRates[env.Value] = env.FailureRate
+		case "kubernetes_version":
+			analysis.KubernetesVersions[env.Value] = env.FailedRuns
+			analysis.KubernetesVersionFailureRates[env.Value] = env.FailureRate
+		}
+	}
+
+	for _, lane := range correlation.Lanes {
+		if lane.FailedRuns > 0 && lane.InfrastructureFailureRate >= 30 {
+			analysis.ResourceIssues = append(analysis.ResourceIssues, fmt.Sprintf(
+				"%s: %.1f%% of test failures are infrastructure failures", lane.JobName, lane.InfrastructureFailureRate))
+		}
 	}
+
+	return analysis
 }
 
-func identifySystemicIssues(correlatedFailures []LLMCorrelatedFailure) []LLMSystemicIssue {
-	return []LLMSystemicIssue{}
+// identifySystemicIssues reports tests and signatures failing across lanes, and
+// architectures or Kubernetes versions failing far more often than the others
+func identifySystemicIssues(correlation *healthcheck.FailureCorrelation, correlated []LLMCorrelatedFailure, signatures []LLMSharedSignature, includeEnvironment bool) []LLMSystemicIssue {
+	issues := []LLMSystemicIssue{}
+
+	for _, failure := range correlated {
+		if len(failure.AffectedJobs) < 3 && failure.CorrelationScore < 0.5 {
+			continue
+		}
+		severity := "medium"
+		if len(failure.AffectedJobs) >= 3 && failure.CorrelationScore >= 0.75 {
+			severity = "high"
+		}
+		issues = append(issues, LLMSystemicIssue{
+			IssueType: "cross_lane_test_failure",
+			Description: fmt.Sprintf("%s failed in %d lanes (%.0f%% of the lanes running it): %s",
+				failure.TestName, len(failure.AffectedJobs), failure.CorrelationScore*100, failure.Signature),
+			AffectedJobs: failure.AffectedJobs,
+			Severity:     severity,
+			EvidenceURLs: failure.EvidenceURLs,
+		})
+	}
+
+	for _, signature := range signatures {
+		if len(signature.Tests) < 2 {
+			continue
+		}
+		severity := "medium"
+		if len(signature.AffectedJobs) >= 3 {
+			severity = "high"
+		}
+		issues = append(issues, LLMSystemicIssue{
+			IssueType: "shared_failure_signature",
+			Description: fmt.Sprintf("%d tests failed %d times with the same signature in %d lanes: %s",
+				len(signature.Tests), signature.Failures, len(signature.AffectedJobs), signature.Signature),
+			AffectedJobs: signature.AffectedJobs,
+			Severity:     severity,
+			EvidenceURLs: signature.EvidenceURLs,
+		})
+	}
+
+	if includeEnvironment {
+		issues = append(issues, environmentIssues(correlation)...)
+	}
+
+	return issues
 }
 
+// environmentIssues reports the architectures and Kubernetes versions whose lanes fail at
+// least twice as often, and 15 points more often, as the other lanes
+func environmentIssues(correlation *healthcheck.FailureCorrelation) []LLMSystemicIssue {
+	issues := []LLMSystemicIssue{}
+	failedRuns := make(map[string][]string)
+	for _, lane := range correlation.Lanes {
+		failedRuns[lane.JobName] = lane.FailedRunURLs
+	}
+
+	for _, env := range correlation.Environments {
+		otherRuns, otherFailed := 0, 0
+		for _, other := range correlation.Environments {
+			if other.Dimension == env.Dimension && other.Value != env.Value {
+				otherRuns += other.TotalRuns
+				otherFailed += other.FailedRuns
+			}
+		}
+		if otherRuns == 0 || env.TotalRuns == 0 {
+			continue
+		}
+		otherRate := float64(otherFailed) / float64(otherRuns) * 100
+		if env.FailureRate < 2*otherRate || env.FailureRate-otherRate < 15 {
+			continue
+		}
+
+		var evidence []string
+		for _, lane := range env.Lanes {
+			evidence = append(evidence, failedRuns[lane]...)
+		}
+		name := env.Value
+		if env.Dimension == "kubernetes_version" {
+			name = "k8s-" + env.Value
+		}
+		issues = append(issues, LLMSystemicIssue{
+			IssueType: "environment_specific",
+			Description: fmt.Sprintf("%s lanes failed %.1f%% of %d runs, against %.1f%% of %d runs elsewhere",
+				name, env.FailureRate, env.TotalRuns, otherRate, otherRuns),
+			AffectedJobs: env.Lanes,
+			Severity:     "high",
+			EvidenceURLs: limitURLs(evidence),
+		})
+	}
+	return issues
+}
+
+// generateCorrelationRecommendations suggests where to start based on the systemic issues found
 func generateCorrelationRecommendations(analysis LLMCorrelationAnalysis) []string {
-	return []string{"Continue monitoring for correlation patterns"}
+	recommendations := []string{}
+	if len(analysis.FailedLanes) > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("%d matching lanes could not be fetched and are missing from the correlation - see failed_lanes and retry", len(analysis.FailedLanes)))
+	}
+	if analysis.LanesAnalyzed == 0 {
+		if len(analysis.FailedLanes) == 0 {
+			recommendations = append(recommendations, "No runs found for the matching lanes in the time window - widen time_window or job_pattern")
+		}
+		return recommendations
+	}
+
+	issueTypes := make(map[string][]string)
+	for _, issue := range analysis.SystemicIssues {
+		issueTypes[issue.IssueType] = append(issueTypes[issue.IssueType], issue.Description)
+	}
+
+	if len(issueTypes["cross_lane_test_failure"]) > 0 {
+		recommendations = append(recommendations, "Tests failing across lanes at once point to a regression or a shared dependency rather than flakiness - check the evidence runs and recently merged changes")
+	}
+	if len(issueTypes["shared_failure_signature"]) > 0 {
+		recommendations = append(recommendations, "Start with the shared failure signatures: a single root cause explains the failures of several tests")
+	}
+	for _, description := range issueTypes["environment_specific"] {
+		recommendations = append(recommendations, "Failures concentrate on one environment - check its cluster provider, images and version-specific changes: "+description)
+	}
+	if len(analysis.EnvironmentAnalysis.ResourceIssues) > 0 {
+		recommendations = append(recommendations, "High infrastructure failure rates on some lanes - check cluster capacity and provisioning")
+	}
+
+	if len(recommendations) == 0 {
+		recommendations = append(recommendations, "No failures correlated across lanes - failures look lane-specific, continue monitoring for correlation patterns")
+	}
+	return recommendations
+}
+
+// limitURLs returns at most maxEvidenceURLs of urls
+func limitURLs(urls []string) []string {
+	if len(urls) > maxEvidenceURLs {
+		return urls[:maxEvidenceURLs]
+	}
+	if urls == nil {
+		return []string{}
+	}
+	return urls
 }
 
-// Placeholder implementations for quarantine analysis
-func analyzeQuarantineStatus(testName string, results *healthcheck.Results) LLMQuarantineStatus {
-	return LLMQuarantineStatus{
-		TestName:          testName,
-		Status:            "active",
-		EffectivenessScore: 0.0,
-		RecommendedAction: "monitor",
+// analyzeQuarantineStatus classifies a quarantined test by its runs in quarantine and regular lanes
+func analyzeQuarantineStatus(test healthcheck.QuarantinedTest) LLMQuarantineStatus {
+	quarantine, regular := test.QuarantineLanes, test.RegularLanes
+	status := LLMQuarantineStatus{
+		TestName:               test.TestName,
+		TestID:                 test.TestID,
+		SIG:                    test.SIG,
+		QuarantinedSince:       test.DateQuarantined,
+		EffectivenessScore:     test.ContainedFailures,
+		QuarantineLaneRuns:     quarantine.Passes + quarantine.Failures,
+		QuarantineLaneFailures: quarantine.Failures,
+		RegularLaneRuns:        regular.Passes + regular.Failures,
+		RegularLaneFailures:    regular.Failures,
+		StableSince:            test.StableSince,
+		StablePasses:           test.StablePasses,
+		LastFailure:            quarantine.LastFailure,
+	}
+	if regular.LastFailure > status.LastFailure {
+		status.LastFailure = regular.LastFailure
+	}
+	runs := status.QuarantineLaneRuns + status.RegularLaneRuns
+	failures := quarantine.Failures + regular.Failures
+
+	switch {
+	case runs == 0:
+		// Renamed or removed tests, or quarantine lanes that did not run
+		status.Status = "not_run"
+		status.RecommendedAction = "verify_test_exists"
+		status.EvidenceURLs = []string{}
+	case regular.Failures > 0:
+		// The quarantine does not keep the test out of regular lanes
+		status.Status = "leaking"
+		status.RecommendedAction = "fix_quarantine"
+		status.EvidenceURLs = limitURLs(regular.FailureURLs)
+	case test.Stable:
+		status.Status = "stable"
+		status.RecommendedAction = "unquarantine"
+		status.EvidenceURLs = limitURLs(append(append([]string{}, quarantine.PassURLs...), regular.PassURLs...))
+	case failures == 0:
+		status.Status = "insufficient_data"
+		status.RecommendedAction = "monitor"
+		status.EvidenceURLs = limitURLs(append(append([]string{}, quarantine.PassURLs...), regular.PassURLs...))
+	case float64(failures)/float64(runs) >= 0.5:
+		status.Status = "failing"
+		status.RecommendedAction = "fix_test"
+		status.EvidenceURLs = limitURLs(quarantine.FailureURLs)
+	default:
+		status.Status = "flaky"
+		status.RecommendedAction = "keep_quarantined"
+		status.EvidenceURLs = limitURLs(quarantine.FailureURLs)
 	}
+	return status
 }
 
+// quarantineCandidate summarizes a flaky test that is not quarantined, with the runs in
+// which it passed and failed on the same code as evidence
+func quarantineCandidate(candidate healthcheck.QuarantineCandidate) LLMQuarantineCandidate {
+	var urls []string
+	for _, evidence := range candidate.Flakes.Evidence {
+		urls = append(urls, evidence.FailedRuns...)
+		urls = append(urls, evidence.PassedRuns...)
+	}
+	return LLMQuarantineCandidate{
+		TestName:        candidate.TestName,
+		Lanes:           candidate.Lanes,
+		Runs:            candidate.Outcomes.Passes + candidate.Outcomes.Failures,
+		Failures:        candidate.Outcomes.Failures,
+		FlakeRate:       candidate.FlakeRate,
+		FlakyCommitSets: candidate.Flakes.FlakyCommitSets,
+		EvidenceURLs:    limitURLs(urls),
+	}
+}
+
+// calculateQuarantineEffectiveness rates the share of quarantined test failures that
+// happened in quarantine lanes rather than in regular lanes
 func calculateQuarantineEffectiveness(quarantines []LLMQuarantineStatus) string {
-	if len(quarantines) == 0 {
+	runs, failures, contained := 0, 0, 0
+	for _, quarantine := range quarantines {
+		runs += quarantine.QuarantineLaneRuns + quarantine.RegularLaneRuns
+		failures += quarantine.QuarantineLaneFailures + quarantine.RegularLaneFailures
+		contained += quarantine.QuarantineLaneFailures
+	}
+	if runs == 0 {
 		return "no_data"
 	}
-	return "moderate"
+	if failures == 0 {
+		return "high"
+	}
+
+	score := float64(contained) / float64(failures)
+	switch {
+	case score >= 0.9:
+		return "high"
+	case score >= 0.5:
+		return "moderate"
+	default:
+		return "low"
+	}
 }
 
-func generateQuarantineRecommendations(analysis LLMQuarantineAnalysis) []LLMQuarantineRecommendation {
-	return []LLMQuarantineRecommendation{}
+// formatStableSince shortens the start of a stability measurement to its date
+func formatStableSince(since string) string {
+	if t, err := time.Parse(time.RFC3339, since); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return since
+}
+
+// quarantinePriorities orders recommendations by priority
+var quarantinePriorities = map[string]int{"high": 0, "medium": 1, "low": 2}
+
+// generateQuarantineRecommendations turns the status of quarantined tests and the flaky
+// candidates into actions, each with the runs supporting it
+func generateQuarantineRecommendations(analysis LLMQuarantineAnalysis, window string) []LLMQuarantineRecommendation {
+	recommendations := []LLMQuarantineRecommendation{}
+
+	for _, status := range analysis.ActiveQuarantines {
+		recommendation := LLMQuarantineRecommendation{
+			TestName:     status.TestName,
+			Action:       status.RecommendedAction,
+			EvidenceURLs: status.EvidenceURLs,
+		}
+		switch status.Status {
+		case "leaking":
+			recommendation.Priority = "high"
+			recommendation.Reasoning = fmt.Sprintf("Quarantined but failed %d of %d runs in regular lanes within %s; make sure the test is skipped outside quarantine lanes",
+				status.RegularLaneFailures, status.RegularLaneRuns, window)
+		case "stable":
+			recommendation.Priority = "medium"
+			recommendation.Reasoning = fmt.Sprintf("Passed all %d runs since %s; the test can be un-quarantined",
+				status.StablePasses, formatStableSince(status.StableSince))
+			if status.QuarantinedSince != "" {
+				recommendation.Reasoning += fmt.Sprintf(" (quarantined since %s)", status.QuarantinedSince)
+			}
+		case "failing":
+			recommendation.Priority = "medium"
+			recommendation.Reasoning = fmt.Sprintf("Failed %d of %d runs in quarantine lanes within %s; the test is broken rather than flaky and needs a fix",
+				status.QuarantineLaneFailures, status.QuarantineLaneRuns, window)
+		case "not_run":
+			recommendation.Priority = "low"
+			recommendation.Reasoning = fmt.Sprintf("Did not run in any analyzed lane within %s; check whether the test was renamed or removed", window)
+		default:
+			continue
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	for _, candidate := range analysis.QuarantineCandidates {
+		priority := "medium"
+		if candidate.FlakeRate >= 20 {
+			priority = "high"
+		}
+		recommendations = append(recommendations, LLMQuarantineRecommendation{
+			TestName: candidate.TestName,
+			Action:   "quarantine",
+			Reasoning: fmt.Sprintf("Not quarantined but failed %d of %d runs (%.1f%%) within %s, passing and failing on the same code in %d commit sets",
+				candidate.Failures, candidate.Runs, candidate.FlakeRate, window, candidate.FlakyCommitSets),
+			Priority:     priority,
+			EvidenceURLs: candidate.EvidenceURLs,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return quarantinePriorities[recommendations[i].Priority] < quarantinePriorities[recommendations[j].Priority]
+	})
+	return recommendations
 }
 
 // Placeholder implementations for impact assessment
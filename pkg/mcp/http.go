@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// HTTP endpoints of the MCP server
+const (
+	StreamableHTTPPath = "/mcp"     // Streamable HTTP transport
+	SSEPath            = "/sse"     // Event stream of the SSE transport
+	SSEMessagePath     = "/message" // Client messages of the SSE transport
+)
+
+// HTTPConfig configures the HTTP transport of the MCP server
+type HTTPConfig struct {
+	Addr            string        // host:port to listen on
+	Token           string        // Bearer token clients must send, empty to accept all clients
+	ShutdownTimeout time.Duration // How long open requests may run after shutdown starts
+}
+
+// ListenAndServe serves the MCP server over HTTP, with the streamable HTTP transport at
+// StreamableHTTPPath and the SSE transport at SSEPath, so that several clients can share
+// one server. When ctx is cancelled, SSE sessions are closed and open requests get
+// config.ShutdownTimeout to complete before their connections are closed.
+func (s *HealthcheckMCPServer) ListenAndServe(ctx context.Context, config HTTPConfig) error {
+	httpServer := &http.Server{
+		Addr:              config.Addr,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	streamable := server.NewStreamableHTTPServer(s.server)
+	// The message endpoint is announced relative to the server, whatever host clients use
+	sse := server.NewSSEServer(s.server,
+		server.WithSSEEndpoint(SSEPath),
+		server.WithMessageEndpoint(SSEMessagePath),
+		server.WithUseFullURLForMessageEndpoint(false),
+		server.WithKeepAlive(true),
+		server.WithHTTPServer(httpServer),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle(StreamableHTTPPath, streamable)
+	mux.Handle(SSEPath, sse.SSEHandler())
+	mux.Handle(SSEMessagePath, sse.MessageHandler())
+	httpServer.Handler = requireBearerToken(config.Token, mux)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to serve MCP over HTTP: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	// Shutting down the SSE server closes its sessions and then the shared HTTP server
+	if err := sse.Shutdown(shutdownCtx); err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("failed to shut down MCP server: %w", err)
+		}
+		// Streams still open after the timeout are closed forcibly
+		httpServer.Close()
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve MCP over HTTP: %w", err)
+	}
+	return nil
+}
+
+// requireBearerToken rejects requests without the bearer token, unless token is empty
+func requireBearerToken(token string, next http.Handler) http.Handler {
+	if token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="healthcheck-mcp"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"healthcheck/pkg/healthcheck"
 
@@ -14,11 +17,24 @@ import (
 // HealthcheckMCPServer provides MCP tools for KubeVirt CI health analysis
 type HealthcheckMCPServer struct {
 	server *server.MCPServer
+	config healthcheck.ProwConfig
+	source healthcheck.Source
+	// parallel is the number of job runs fetched concurrently
+	parallel int
+	// quarantineSource is a KubeVirt checkout scanned for quarantined tests instead of the report
+	quarantineSource string
 }
 
-// NewHealthcheckMCPServer creates a new MCP server for healthcheck analysis
-func NewHealthcheckMCPServer() *HealthcheckMCPServer {
-	s := &HealthcheckMCPServer{}
+// NewHealthcheckMCPServer creates a new MCP server for healthcheck analysis of the given Prow
+// deployment. Quarantined tests are read from the KubeVirt checkout at quarantineSource
+// when it is set, and from the published report otherwise.
+func NewHealthcheckMCPServer(config healthcheck.ProwConfig, source healthcheck.Source, parallel int, quarantineSource string) *HealthcheckMCPServer {
+	s := &HealthcheckMCPServer{
+		config:           config,
+		source:           source,
+		parallel:         parallel,
+		quarantineSource: quarantineSource,
+	}
 	
 	mcpServer := server.NewMCPServer(
 		"healthcheck-mcp",
@@ -40,6 +56,9 @@ func (s *HealthcheckMCPServer) Serve() error {
 
 // registerTools registers all available MCP tools
 func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
+	// Aliases include those of the config file, which is loaded before the server is created
+	aliases := strings.Join(healthcheck.JobAliasNames(), ", ")
+
 	// Tool 1: Analyze job lane with summary
 	analyzeJobLaneTool := mcp.NewTool(
 		"analyze_job_lane",
@@ -47,6 +66,7 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 		mcp.WithString("job_name", mcp.Description("Name of the CI job to analyze"), mcp.Required()),
 		mcp.WithString("since", mcp.Description("Time period to analyze (e.g., '24h', '7d', '1w')"), mcp.DefaultString("24h")),
 		mcp.WithBoolean("include_details", mcp.Description("Include detailed failure information"), mcp.DefaultBool(true)),
+		mcp.WithString("label", mcp.Description("Only include tests carrying one of these comma-separated Ginkgo labels (e.g., 'sig-compute')")),
 	)
 	mcpServer.AddTool(analyzeJobLaneTool, s.analyzeJobLane)
 
@@ -57,6 +77,7 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 		mcp.WithString("job_name", mcp.Description("Name of the CI job"), mcp.Required()),
 		mcp.WithNumber("limit", mcp.Description("Number of recent runs to analyze"), mcp.DefaultNumber(10), mcp.Min(1), mcp.Max(100)),
 		mcp.WithBoolean("include_stack_traces", mcp.Description("Include failure stack traces"), mcp.DefaultBool(false)),
+		mcp.WithString("label", mcp.Description("Only include tests carrying one of these comma-separated Ginkgo labels (e.g., 'sig-compute')")),
 	)
 	mcpServer.AddTool(getJobFailuresTool, s.getJobFailures)
 
@@ -64,9 +85,10 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 	analyzeMergeFailuresTool := mcp.NewTool(
 		"analyze_merge_failures",
 		mcp.WithDescription("Analyze test failures across all merge-time jobs using ci-health data"),
-		mcp.WithString("job_filter", mcp.Description("Job filter regex or alias (compute, network, storage, main, etc.)"), mcp.DefaultString(".*")),
+		mcp.WithString("job_filter", mcp.Description("Job filter regex or alias ("+aliases+")"), mcp.DefaultString(".*")),
 		mcp.WithString("test_filter", mcp.Description("Test name filter regex"), mcp.DefaultString(".*")),
 		mcp.WithBoolean("include_quarantined", mcp.Description("Include quarantined test information"), mcp.DefaultBool(true)),
+		mcp.WithString("label", mcp.Description("Only include tests carrying one of these comma-separated Ginkgo labels (e.g., 'sig-compute')")),
 	)
 	mcpServer.AddTool(analyzeMergeFailuresTool, s.analyzeMergeFailures)
 
@@ -75,7 +97,7 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 		"search_failure_patterns",
 		mcp.WithDescription("Search for specific failure patterns across jobs"),
 		mcp.WithString("pattern", mcp.Description("Regex pattern to search for in test names or failure messages"), mcp.Required()),
-		mcp.WithString("job_filter", mcp.Description("Job filter regex or alias"), mcp.DefaultString(".*")),
+		mcp.WithString("job_filter", mcp.Description("Job filter regex or alias ("+aliases+")"), mcp.DefaultString(".*")),
 		mcp.WithString("search_in", mcp.Description("Where to search for the pattern"), mcp.Enum("test_names", "failure_messages", "both"), mcp.DefaultString("test_names")),
 	)
 	mcpServer.AddTool(searchFailurePatternsTool, s.searchFailurePatterns)
@@ -106,16 +128,16 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 		mcp.WithDescription("Analyze failure trends and patterns over time periods"),
 		mcp.WithString("job_name", mcp.Description("Name of the CI job to analyze"), mcp.Required()),
 		mcp.WithString("trend_period", mcp.Description("Time period for trend analysis (e.g., '7d', '14d', '30d')"), mcp.DefaultString("14d")),
-		mcp.WithBoolean("include_flakiness", mcp.Description("Include flakiness analysis"), mcp.DefaultBool(true)),
+		mcp.WithBoolean("include_flakiness", mcp.Description("Include flakiness analysis: tests that passed and failed on the same commits, with the evidence runs"), mcp.DefaultBool(true)),
 	)
 	mcpServer.AddTool(analyzeFailureTrendsTool, s.analyzeFailureTrends)
 
 	// Tool 8: Cross-job failure correlation
 	analyzeFailureCorrelationTool := mcp.NewTool(
 		"analyze_failure_correlation",
-		mcp.WithDescription("Analyze failures across multiple jobs to identify systemic issues"),
-		mcp.WithString("job_pattern", mcp.Description("Job pattern or alias to analyze (e.g., 'compute', 'storage')"), mcp.DefaultString(".*")),
-		mcp.WithString("time_window", mcp.Description("Time window for correlation analysis"), mcp.DefaultString("24h")),
+		mcp.WithDescription("Analyze failures across multiple jobs to identify systemic issues: tests and failure signatures failing in several lanes at once, and failure rates by architecture and Kubernetes version, with evidence URLs"),
+		mcp.WithString("job_pattern", mcp.Description("Job regex or alias to analyze ("+aliases+")"), mcp.DefaultString(".*")),
+		mcp.WithString("time_window", mcp.Description("Time window for correlation analysis (e.g., '24h', '3d', '1w')"), mcp.DefaultString("24h")),
 		mcp.WithBoolean("include_environment_analysis", mcp.Description("Include environment-specific failure analysis"), mcp.DefaultBool(true)),
 	)
 	mcpServer.AddTool(analyzeFailureCorrelationTool, s.analyzeFailureCorrelation)
@@ -123,8 +145,12 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 	// Tool 9: Quarantine intelligence
 	analyzeQuarantineIntelligenceTool := mcp.NewTool(
 		"analyze_quarantine_intelligence",
-		mcp.WithDescription("Provide intelligent analysis of quarantined tests and recommendations"),
-		mcp.WithString("scope", mcp.Description("Analysis scope: 'all', 'job', or specific job name"), mcp.DefaultString("all")),
+		mcp.WithDescription("Measure how often quarantined tests still run and fail in quarantine lanes versus regular lanes, recommend un-quarantining tests stable for stable_days and quarantining flaky tests above flake_threshold, with run URLs as evidence"),
+		mcp.WithString("scope", mcp.Description("Regular lanes to analyze: 'all', a job name, regex or alias ("+aliases+")"), mcp.DefaultString("all")),
+		mcp.WithString("quarantine_lanes", mcp.Description("Regex matching the lanes that run quarantined tests"), mcp.DefaultString(healthcheck.QuarantineLanePattern)),
+		mcp.WithNumber("stable_days", mcp.Description("Days a quarantined test must pass without failure to be un-quarantined; also the analysis window"), mcp.DefaultNumber(14)),
+		mcp.WithNumber("flake_threshold", mcp.Description("Failure percentage above which a flaky test that is not quarantined should be"), mcp.DefaultNumber(5)),
+		mcp.WithNumber("min_executions", mcp.Description("Passes a quarantined test needs, without a failure since it was quarantined or since the window start, to be un-quarantined"), mcp.DefaultNumber(3)),
 		mcp.WithBoolean("include_recommendations", mcp.Description("Include quarantine action recommendations"), mcp.DefaultBool(true)),
 	)
 	mcpServer.AddTool(analyzeQuarantineIntelligenceTool, s.analyzeQuarantineIntelligence)
@@ -148,6 +174,17 @@ func (s *HealthcheckMCPServer) registerTools(mcpServer *server.MCPServer) {
 		mcp.WithBoolean("include_recommendations", mcp.Description("Include actionable recommendations"), mcp.DefaultBool(true)),
 	)
 	mcpServer.AddTool(generateFailureReportTool, s.generateFailureReport)
+
+	// Tool 12: Test history across lanes
+	getTestHistoryTool := mcp.NewTool(
+		"get_test_history",
+		mcp.WithDescription("Show where tests matching a name regex ran across all lanes, with pass/fail counts per lane, first and last failure and the failure signature distribution"),
+		mcp.WithString("test_pattern", mcp.Description("Regex matching the test names"), mcp.Required()),
+		mcp.WithString("job_filter", mcp.Description("Only search lanes matching this regex or alias (e.g., 'compute', 'arm64')"), mcp.DefaultString(".*")),
+		mcp.WithString("time_period", mcp.Description("Time period to analyze (e.g., '24h', '3d', '1w')"), mcp.DefaultString("1w")),
+		mcp.WithNumber("limit", mcp.Description("Maximum number of runs analyzed per lane"), mcp.DefaultNumber(100)),
+	)
+	mcpServer.AddTool(getTestHistoryTool, s.getTestHistory)
 }
 
 // analyzeJobLane implements the analyze_job_lane tool
@@ -159,6 +196,7 @@ func (s *HealthcheckMCPServer) analyzeJobLane(ctx context.Context, request mcp.C
 
 	since := mcp.ParseString(request, "since", "24h")
 	includeDetails := mcp.ParseBoolean(request, "include_details", true)
+	labels := parseLabels(mcp.ParseString(request, "label", ""))
 
 	// Parse time period
 	timePeriod, err := healthcheck.ParseTimePeriod(since)
@@ -167,19 +205,20 @@ func (s *HealthcheckMCPServer) analyzeJobLane(ctx context.Context, request mcp.C
 	}
 
 	// Fetch job history
-	runs, err := healthcheck.FetchJobHistoryWithTimePeriod(jobName, timePeriod, 1000)
+	runs, err := healthcheck.FetchJobHistoryWithTimePeriod(ctx, s.source, jobName, timePeriod, 1000)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch job history: %v", err)), nil
 	}
 
 	// Analyze runs
-	summary, err := healthcheck.AnalyzeLaneRuns(runs)
+	summary, err := healthcheck.AnalyzeLaneRuns(ctx, s.source, runs, s.parallel)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze lane runs: %v", err)), nil
 	}
+	summary = healthcheck.FilterLaneSummaryByLabels(summary, labels)
 
 	// Format response for LLM
-	response := formatLaneSummaryForLLM(jobName, summary, includeDetails)
+	response := formatLaneSummaryForLLM(ctx, s.source, jobName, summary, includeDetails)
 	
 	jsonResponse, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -198,15 +237,23 @@ func (s *HealthcheckMCPServer) getJobFailures(ctx context.Context, request mcp.C
 
 	limit := int(mcp.ParseFloat64(request, "limit", 10))
 	includeStackTraces := mcp.ParseBoolean(request, "include_stack_traces", false)
+	labels := parseLabels(mcp.ParseString(request, "label", ""))
 
 	// Fetch job history
-	runs, err := healthcheck.FetchJobHistory(jobName, limit)
+	runs, err := healthcheck.FetchJobHistory(ctx, s.source, jobName, limit)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch job history: %v", err)), nil
 	}
 
+	// Fetch the test results of the runs
+	summary, err := healthcheck.AnalyzeLaneRuns(ctx, s.source, runs, s.parallel)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze lane runs: %v", err)), nil
+	}
+	summary = healthcheck.FilterLaneSummaryByLabels(summary, labels)
+
 	// Format detailed failure information
-	response := formatJobFailuresForLLM(jobName, runs, includeStackTraces)
+	response := formatJobFailuresForLLM(ctx, s.source, jobName, summary.Runs, includeStackTraces)
 	
 	jsonResponse, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -221,9 +268,10 @@ func (s *HealthcheckMCPServer) analyzeMergeFailures(ctx context.Context, request
 	jobFilter := mcp.ParseString(request, "job_filter", ".*")
 	testFilter := mcp.ParseString(request, "test_filter", ".*")
 	includeQuarantined := mcp.ParseBoolean(request, "include_quarantined", true)
+	labels := parseLabels(mcp.ParseString(request, "label", ""))
 
 	// Fetch ci-health results
-	results, err := healthcheck.FetchResults(healthcheck.HealthURL)
+	results, err := healthcheck.FetchResults(ctx, s.config.HealthURL())
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch ci-health results: %v", err)), nil
 	}
@@ -233,15 +281,20 @@ func (s *HealthcheckMCPServer) analyzeMergeFailures(ctx context.Context, request
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to build processor config: %v", err)), nil
 	}
+	config.Source = s.source
+	config.QuarantinedTestsURL = s.config.QuarantinedTestsURL()
+	config.QuarantineSource = s.quarantineSource
+	config.Parallel = s.parallel
+	config.Labels = labels
 
 	// Process failures
-	result, err := healthcheck.ProcessFailures(results, config)
+	result, err := healthcheck.ProcessFailures(ctx, results, config)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to process failures: %v", err)), nil
 	}
 
 	// Format response for LLM
-	response := formatMergeFailuresForLLM(result, jobFilter, testFilter)
+	response := formatMergeFailuresForLLM(result, jobFilter, testFilter, labels)
 	
 	jsonResponse, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -262,7 +315,7 @@ func (s *HealthcheckMCPServer) searchFailurePatterns(ctx context.Context, reques
 	searchIn := mcp.ParseString(request, "search_in", "test_names")
 
 	// Fetch ci-health results
-	results, err := healthcheck.FetchResults(healthcheck.HealthURL)
+	results, err := healthcheck.FetchResults(ctx, s.config.HealthURL())
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch ci-health results: %v", err)), nil
 	}
@@ -303,23 +356,23 @@ func (s *HealthcheckMCPServer) compareTimePeriods(ctx context.Context, request m
 	}
 
 	// Fetch data for both periods
-	recentRuns, err := healthcheck.FetchJobHistoryWithTimePeriod(jobName, recentDuration, 1000)
+	recentRuns, err := healthcheck.FetchJobHistoryWithTimePeriod(ctx, s.source, jobName, recentDuration, 1000)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch recent data: %v", err)), nil
 	}
 
-	comparisonRuns, err := healthcheck.FetchJobHistoryWithTimePeriod(jobName, comparisonDuration, 1000)
+	comparisonRuns, err := healthcheck.FetchJobHistoryWithTimePeriod(ctx, s.source, jobName, comparisonDuration, 1000)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch comparison data: %v", err)), nil
 	}
 
 	// Analyze both periods
-	recentSummary, err := healthcheck.AnalyzeLaneRuns(recentRuns)
+	recentSummary, err := healthcheck.AnalyzeLaneRuns(ctx, s.source, recentRuns, s.parallel)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze recent data: %v", err)), nil
 	}
 
-	comparisonSummary, err := healthcheck.AnalyzeLaneRuns(comparisonRuns)
+	comparisonSummary, err := healthcheck.AnalyzeLaneRuns(ctx, s.source, comparisonRuns, s.parallel)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze comparison data: %v", err)), nil
 	}
@@ -356,7 +409,7 @@ func (s *HealthcheckMCPServer) getFailureSourceContext(ctx context.Context, requ
 	}
 
 	// Extract repository and commit information from job URL
-	repoInfo, err := ExtractRepositoryInfo(jobURL)
+	repoInfo, err := ExtractRepositoryInfo(ctx, s.source, jobURL)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to extract repository info: %v", err)), nil
 	}
@@ -389,13 +442,19 @@ func (s *HealthcheckMCPServer) analyzeFailureTrends(ctx context.Context, request
 	}
 
 	// Fetch historical data for trend analysis
-	runs, err := healthcheck.FetchJobHistoryWithTimePeriod(jobName, trendDuration, 500) // Larger limit for trend analysis
+	runs, err := healthcheck.FetchJobHistoryWithTimePeriod(ctx, s.source, jobName, trendDuration, 500) // Larger limit for trend analysis
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch job history: %v", err)), nil
 	}
 
+	// Fetch the test results of the runs, which flakiness and failure patterns are based on
+	summary, err := healthcheck.AnalyzeLaneRuns(ctx, s.source, runs, s.parallel)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze lane runs: %v", err)), nil
+	}
+
 	// Analyze trends
-	trendAnalysis := analyzeTrendsFromRuns(runs, includeFlakiness)
+	trendAnalysis := analyzeTrendsFromRuns(summary.Runs, includeFlakiness)
 	trendAnalysis.JobName = jobName
 	trendAnalysis.TrendPeriod = trendPeriod
 
@@ -407,20 +466,83 @@ func (s *HealthcheckMCPServer) analyzeFailureTrends(ctx context.Context, request
 	return mcp.NewToolResultText(string(jsonResponse)), nil
 }
 
+// getTestHistory implements the get_test_history tool
+func (s *HealthcheckMCPServer) getTestHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	testPattern := mcp.ParseString(request, "test_pattern", "")
+	if testPattern == "" {
+		return mcp.NewToolResultError("test_pattern parameter is required"), nil
+	}
+	jobFilter := mcp.ParseString(request, "job_filter", ".*")
+	timePeriod := mcp.ParseString(request, "time_period", "1w")
+	limit := mcp.ParseInt(request, "limit", 100)
+
+	testRegex, err := regexp.Compile(testPattern)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid test pattern: %v", err)), nil
+	}
+	jobFilter = healthcheck.ResolveJobAlias(jobFilter)
+	jobRegex, err := regexp.Compile(jobFilter)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid job filter: %v", err)), nil
+	}
+	period, err := healthcheck.ParseTimePeriod(timePeriod)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid time period: %v", err)), nil
+	}
+
+	lanes, err := healthcheck.ActiveLanes(ctx, s.source, s.config.HealthURL(), jobRegex, period)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list lanes: %v", err)), nil
+	}
+
+	history, err := healthcheck.AnalyzeTestHistory(ctx, s.source, lanes, healthcheck.TestHistoryConfig{
+		TestRegex:  testRegex,
+		TimePeriod: period,
+		RunLimit:   limit,
+		Parallel:   s.parallel,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze test history: %v", err)), nil
+	}
+
+	jsonResponse, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonResponse)), nil
+}
+
 // analyzeFailureCorrelation implements the analyze_failure_correlation tool
 func (s *HealthcheckMCPServer) analyzeFailureCorrelation(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	jobPattern := mcp.ParseString(request, "job_pattern", ".*")
 	timeWindow := mcp.ParseString(request, "time_window", "24h")
 	includeEnvironmentAnalysis := mcp.ParseBoolean(request, "include_environment_analysis", true)
 
-	// Fetch ci-health results for cross-job analysis
-	results, err := healthcheck.FetchResults(healthcheck.HealthURL)
+	timePeriod, err := healthcheck.ParseTimePeriod(timeWindow)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch ci-health results: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid time window: %v", err)), nil
+	}
+	jobRegex, err := regexp.Compile(healthcheck.ResolveJobAlias(jobPattern))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid job pattern: %v", err)), nil
 	}
 
-	// Analyze correlation across jobs
-	correlationAnalysis := analyzeFailureCorrelationAcrossJobs(results, jobPattern, timeWindow, includeEnvironmentAnalysis)
+	lanes, err := healthcheck.KnownLanes(ctx, s.source, s.config.HealthURL(), jobRegex)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list lanes: %v", err)), nil
+	}
+
+	// Correlate the failures of the matching lanes within the time window
+	correlation, err := healthcheck.CorrelateFailures(ctx, s.source, lanes, healthcheck.CorrelationConfig{
+		TimePeriod: timePeriod,
+		RunLimit:   100,
+		Parallel:   s.parallel,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to correlate failures: %v", err)), nil
+	}
+	correlationAnalysis := analyzeFailureCorrelationAcrossJobs(correlation, jobPattern, timeWindow, includeEnvironmentAnalysis)
 
 	jsonResponse, err := json.MarshalIndent(correlationAnalysis, "", "  ")
 	if err != nil {
@@ -433,22 +555,68 @@ func (s *HealthcheckMCPServer) analyzeFailureCorrelation(ctx context.Context, re
 // analyzeQuarantineIntelligence implements the analyze_quarantine_intelligence tool
 func (s *HealthcheckMCPServer) analyzeQuarantineIntelligence(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	scope := mcp.ParseString(request, "scope", "all")
+	quarantinePattern := mcp.ParseString(request, "quarantine_lanes", healthcheck.QuarantineLanePattern)
+	stableDays := mcp.ParseInt(request, "stable_days", 14)
+	flakeThreshold := mcp.ParseFloat64(request, "flake_threshold", 5)
+	minExecutions := mcp.ParseInt(request, "min_executions", 3)
 	includeRecommendations := mcp.ParseBoolean(request, "include_recommendations", true)
 
+	if stableDays <= 0 {
+		return mcp.NewToolResultError("stable_days must be positive"), nil
+	}
+	if minExecutions <= 0 {
+		return mcp.NewToolResultError("min_executions must be positive"), nil
+	}
+	scopePattern := healthcheck.ResolveJobAlias(scope)
+	if scope == "all" {
+		scopePattern = ".*"
+	}
+	scopeRegex, err := regexp.Compile(scopePattern)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid scope: %v", err)), nil
+	}
+	quarantineRegex, err := regexp.Compile(quarantinePattern)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid quarantine lanes pattern: %v", err)), nil
+	}
+
 	// Fetch quarantined tests
-	quarantinedTests, err := healthcheck.FetchQuarantinedTests()
+	quarantinedTests, err := healthcheck.LoadQuarantinedTests(ctx, s.config.QuarantinedTestsURL(), s.quarantineSource)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch quarantined tests: %v", err)), nil
 	}
 
-	// Fetch current ci-health data for analysis
-	results, err := healthcheck.FetchResults(healthcheck.HealthURL)
+	// Quarantined tests only run in quarantine lanes, whatever the scope. Lanes are listed
+	// whether they fail or not, as quiet lanes are the evidence of stability.
+	period := time.Duration(stableDays) * 24 * time.Hour
+	quarantineLanes, err := healthcheck.ActiveLanes(ctx, s.source, s.config.HealthURL(), quarantineRegex, period)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to fetch ci-health results: %v", err)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list lanes: %v", err)), nil
+	}
+	lanes, err := healthcheck.ActiveLanes(ctx, s.source, s.config.HealthURL(), scopeRegex, period)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list lanes: %v", err)), nil
+	}
+	var regularLanes []string
+	for _, lane := range lanes {
+		if !quarantineRegex.MatchString(lane) {
+			regularLanes = append(regularLanes, lane)
+		}
+	}
+
+	report, err := healthcheck.AnalyzeQuarantine(ctx, s.source, quarantineLanes, regularLanes, quarantinedTests, healthcheck.QuarantineConfig{
+		TimePeriod:     period,
+		RunLimit:       100,
+		Parallel:       s.parallel,
+		MinExecutions:  minExecutions,
+		FlakeThreshold: flakeThreshold,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to analyze quarantined tests: %v", err)), nil
 	}
 
 	// Analyze quarantine intelligence
-	quarantineAnalysis := analyzeQuarantineEffectiveness(quarantinedTests, results, scope, includeRecommendations)
+	quarantineAnalysis := analyzeQuarantineEffectiveness(report, scope, includeRecommendations)
 
 	jsonResponse, err := json.MarshalIndent(quarantineAnalysis, "", "  ")
 	if err != nil {